@@ -0,0 +1,201 @@
+// Package caldav syncs tasks with a CalDAV calendar collection, storing each
+// task as a VTODO resource. Syncs are incremental: the ETags remembered from
+// the last sync tell which resources changed on the server, and the tasks'
+// update times which changed here.
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"t7t/internal/ical"
+)
+
+// ErrPrecondition is returned when a resource changed on the server since
+// its ETag was read.
+var ErrPrecondition = errors.New("resource changed on the server")
+
+// Client talks to one calendar collection.
+type Client struct {
+	base     *url.URL
+	username string
+	password string
+	http     *http.Client
+}
+
+// NewClient returns a client for the collection at rawURL. An empty username
+// sends no credentials.
+func NewClient(rawURL, username, password string) (*Client, error) {
+	base, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	return &Client{
+		base:     base,
+		username: username,
+		password: password,
+		http:     &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Resource is a calendar object in the collection, identified by its path.
+type Resource struct {
+	Path string
+	ETag string
+}
+
+func (c *Client) request(method, path string, body []byte, header map[string]string) (*http.Response, error) {
+	target := c.base.ResolveReference(&url.URL{Path: path})
+	req, err := http.NewRequest(method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	return c.http.Do(req)
+}
+
+func statusError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return ErrPrecondition
+	}
+	return fmt.Errorf("caldav: %s %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status)
+}
+
+const propfindETags = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:getetag/><d:resourcetype/></d:prop></d:propfind>`
+
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Status string `xml:"status"`
+			Prop   struct {
+				ETag         string `xml:"getetag"`
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// List returns the calendar objects in the collection with their ETags.
+func (c *Client) List() ([]Resource, error) {
+	resp, err := c.request("PROPFIND", c.base.Path, []byte(propfindETags), map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, statusError(resp)
+	}
+
+	var ms multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	for _, r := range ms.Responses {
+		href, err := url.Parse(r.Href)
+		if err != nil {
+			return nil, err
+		}
+		path := c.base.ResolveReference(href).Path
+		for _, ps := range r.Propstat {
+			if !strings.Contains(ps.Status, " 200 ") || ps.Prop.ResourceType.Collection != nil {
+				continue
+			}
+			resources = append(resources, Resource{Path: path, ETag: ps.Prop.ETag})
+		}
+	}
+	return resources, nil
+}
+
+// Get fetches a calendar object, returning its VTODO and ETag. Objects
+// without a VTODO, such as events, yield a nil component.
+func (c *Client) Get(path string) (*ical.Component, string, error) {
+	resp, err := c.request(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", statusError(resp)
+	}
+
+	cal, err := ical.Decode(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	var todo *ical.Component
+	if todos := cal.Components("VTODO"); len(todos) > 0 {
+		todo = todos[0]
+	}
+	return todo, resp.Header.Get("ETag"), nil
+}
+
+// Put stores a VTODO. With an ETag it only replaces that version of the
+// object, and without one it only creates a new object. It returns the new
+// ETag, which servers may leave out.
+func (c *Client) Put(path string, todo *ical.Component, etag string) (string, error) {
+	header := map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+	if etag != "" {
+		header["If-Match"] = etag
+	} else {
+		header["If-None-Match"] = "*"
+	}
+
+	resp, err := c.request(http.MethodPut, path, []byte(ical.Calendar(todo).String()), header)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return "", statusError(resp)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// Delete removes the given version of an object. Objects already gone count
+// as deleted.
+func (c *Client) Delete(path, etag string) error {
+	header := map[string]string{}
+	if etag != "" {
+		header["If-Match"] = etag
+	}
+	resp, err := c.request(http.MethodDelete, path, nil, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	}
+	return statusError(resp)
+}
+
+// path returns where a new task's object goes in the collection.
+func (c *Client) path(taskID string) string {
+	return c.base.Path + taskID + ".ics"
+}
@@ -0,0 +1,191 @@
+package caldav
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"t7t/internal/ical"
+)
+
+// fakeCollection is an in-process stand-in for a CalDAV calendar collection.
+// It serves PROPFIND, GET, PUT and DELETE on the objects below its path,
+// honoring If-Match and If-None-Match like a real server.
+type fakeCollection struct {
+	t      *testing.T
+	server *httptest.Server
+	path   string
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	version int
+
+	// beforeWrite, if set, runs when a PUT or DELETE arrives, before its
+	// preconditions are checked, to change the collection under it.
+	beforeWrite func()
+}
+
+type fakeObject struct {
+	data []byte
+	etag string
+}
+
+const collectionPath = "/calendars/user/tasks/"
+
+func newFakeCollection(t *testing.T) *fakeCollection {
+	f := &fakeCollection{t: t, path: collectionPath, objects: make(map[string]fakeObject)}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeCollection) client() *Client {
+	c, err := NewClient(f.server.URL+f.path, "", "")
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return c
+}
+
+func (f *fakeCollection) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut || r.Method == http.MethodDelete {
+		f.mu.Lock()
+		hook := f.beforeWrite
+		f.beforeWrite = nil
+		f.mu.Unlock()
+		if hook != nil {
+			hook()
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method == "PROPFIND" {
+		f.propfind(w, r)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, f.path) || r.URL.Path == f.path {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	obj, exists := f.objects[r.URL.Path]
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", obj.etag)
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write(obj.data)
+
+	case http.MethodPut:
+		if match := r.Header.Get("If-Match"); match != "" && (!exists || match != obj.etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		etag := f.store(r.URL.Path, data)
+		w.Header().Set("ETag", etag)
+		if exists {
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			w.WriteHeader(http.StatusCreated)
+		}
+
+	case http.MethodDelete:
+		if !exists {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if match := r.Header.Get("If-Match"); match != "" && match != obj.etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeCollection) propfind(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != f.path || r.Header.Get("Depth") != "1" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?><d:multistatus xmlns:d="DAV:">`)
+	fmt.Fprintf(&b, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`, f.path)
+	paths := make([]string, 0, len(f.objects))
+	for path := range f.objects {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(&b, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag><d:resourcetype/></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+			path, strings.ReplaceAll(f.objects[path].etag, `"`, "&quot;"))
+	}
+	b.WriteString(`</d:multistatus>`)
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, b.String())
+}
+
+// store saves an object under a new ETag. The caller holds f.mu.
+func (f *fakeCollection) store(path string, data []byte) string {
+	f.version++
+	etag := fmt.Sprintf(`"v%d"`, f.version)
+	f.objects[path] = fakeObject{data: data, etag: etag}
+	return etag
+}
+
+// put stores a VTODO as another client of the server would.
+func (f *fakeCollection) put(path string, todo *ical.Component) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.store(path, []byte(ical.Calendar(todo).String()))
+}
+
+// todo returns the VTODO stored at path, or nil.
+func (f *fakeCollection) todo(path string) *ical.Component {
+	f.mu.Lock()
+	obj, ok := f.objects[path]
+	f.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	cal, err := ical.Decode(strings.NewReader(string(obj.data)))
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return cal.Components("VTODO")[0]
+}
+
+func (f *fakeCollection) remove(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.objects, path)
+}
+
+func (f *fakeCollection) len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.objects)
+}
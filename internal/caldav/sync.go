@@ -0,0 +1,311 @@
+package caldav
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"t7t/internal/ical"
+	"t7t/internal/model"
+)
+
+const stateFile = "caldav.json"
+
+// State is what syncing remembers between runs. It's kept next to the data
+// file.
+type State struct {
+	URL      string               `json:"url"`
+	Username string               `json:"username,omitempty"`
+	Items    map[string]ItemState `json:"items"`
+
+	// Skipped holds the ETags of objects that aren't tasks, such as events,
+	// so they're only fetched again when they change.
+	Skipped map[string]string `json:"skipped,omitempty"`
+}
+
+// ItemState links a task to its object on the server, with the object's ETag
+// and the task's update time as of the last sync.
+type ItemState struct {
+	Path    string    `json:"path"`
+	ETag    string    `json:"etag"`
+	Updated time.Time `json:"updated"`
+}
+
+// LoadState reads the sync state kept in dir, or returns an empty one.
+func LoadState(dir string) (*State, error) {
+	state := &State{Items: make(map[string]ItemState)}
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Items == nil {
+		state.Items = make(map[string]ItemState)
+	}
+	return state, nil
+}
+
+func (s *State) Save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, stateFile), data, 0600)
+}
+
+// SetURL points the state at another collection, forgetting what was synced
+// with the previous one.
+func (s *State) SetURL(url string) {
+	if url != s.URL {
+		s.URL = url
+		s.Items = make(map[string]ItemState)
+		s.Skipped = nil
+	}
+}
+
+// Result counts what a sync did. Conflicts names the tasks changed both here
+// and on the server; the newer version was kept for each of them.
+type Result struct {
+	Sent         int
+	Received     int
+	DeletedHere  int
+	DeletedThere int
+	Conflicts    []string
+}
+
+type syncer struct {
+	store  *model.Store
+	client *Client
+	state  *State
+	result Result
+	dirty  bool
+}
+
+// Sync exchanges the changes made here and on the server since the last
+// sync. A task changed on only one side takes that side's version, a task
+// changed on both keeps the newer one, and deletions carry over unless the
+// other side changed the task since.
+func Sync(store *model.Store, client *Client, state *State) (Result, error) {
+	s := &syncer{store: store, client: client, state: state}
+
+	resources, err := client.List()
+	if err != nil {
+		return s.result, err
+	}
+	onServer := make(map[string]string, len(resources))
+	for _, r := range resources {
+		onServer[r.Path] = r.ETag
+	}
+
+	// Objects handled below include those whose tasks are deleted on the
+	// way, which the listing still shows.
+	ids := make([]string, 0, len(state.Items))
+	tracked := make(map[string]bool, len(state.Items))
+	for id, item := range state.Items {
+		ids = append(ids, id)
+		tracked[item.Path] = true
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := s.syncItem(id, onServer); err != nil {
+			return s.finish(err)
+		}
+	}
+	for _, item := range state.Items {
+		tracked[item.Path] = true
+	}
+
+	skipped := state.Skipped
+	state.Skipped = make(map[string]string)
+	for _, r := range resources {
+		switch {
+		case tracked[r.Path]:
+		case skipped[r.Path] == r.ETag && r.ETag != "":
+			state.Skipped[r.Path] = r.ETag
+		default:
+			if err := s.pull(r.Path); err != nil {
+				return s.finish(err)
+			}
+		}
+	}
+
+	for _, task := range slices.Clone(store.Tasks) {
+		if _, ok := state.Items[task.ID]; !ok {
+			if err := s.push(task, client.path(task.ID), ""); err != nil {
+				return s.finish(err)
+			}
+		}
+	}
+
+	return s.finish(nil)
+}
+
+// finish saves what the sync changed here, even when it stopped early, so
+// the state stays in step with the data file.
+func (s *syncer) finish(err error) (Result, error) {
+	if s.dirty {
+		if saveErr := s.store.Save(); err == nil {
+			err = saveErr
+		}
+	}
+	return s.result, err
+}
+
+func (s *syncer) syncItem(id string, onServer map[string]string) error {
+	item := s.state.Items[id]
+	task := s.store.GetTask(id)
+	etag, exists := onServer[item.Path]
+	localChanged := task != nil && !task.UpdatedAt.Equal(item.Updated)
+
+	switch {
+	case !exists && task == nil:
+		delete(s.state.Items, id)
+
+	case !exists:
+		delete(s.state.Items, id)
+		if localChanged {
+			s.result.Conflicts = append(s.result.Conflicts, task.Name)
+			return s.push(task, item.Path, "")
+		}
+		if err := s.store.DeleteTask(id); err != nil {
+			return err
+		}
+		s.result.DeletedHere++
+
+	case task == nil:
+		if etag != item.ETag {
+			// Changed on the server after it was deleted here.
+			delete(s.state.Items, id)
+			return s.pull(item.Path)
+		}
+		if err := s.client.Delete(item.Path, etag); err != nil && !errors.Is(err, ErrPrecondition) {
+			return err
+		}
+		delete(s.state.Items, id)
+		s.result.DeletedThere++
+
+	case etag != item.ETag:
+		todo, newETag, err := s.client.Get(item.Path)
+		if err != nil {
+			return err
+		}
+		if newETag == "" {
+			newETag = etag
+		}
+		if todo == nil {
+			delete(s.state.Items, id)
+			return nil
+		}
+		if localChanged {
+			s.result.Conflicts = append(s.result.Conflicts, task.Name)
+			if task.UpdatedAt.After(ical.LastModified(todo)) {
+				return s.push(task, item.Path, newETag)
+			}
+		}
+		if s.apply(task, todo) {
+			s.result.Received++
+		}
+		s.track(task, item.Path, newETag)
+
+	case localChanged:
+		return s.push(task, item.Path, etag)
+	}
+	return nil
+}
+
+// pull brings in an object not linked to a task yet. Its UID may name a task
+// that's here already, when the sync state was lost, and then the newer
+// version wins.
+func (s *syncer) pull(path string) error {
+	todo, etag, err := s.client.Get(path)
+	if err != nil {
+		return err
+	}
+	if todo == nil {
+		s.state.Skipped[path] = etag
+		return nil
+	}
+
+	task := s.store.GetTask(todo.Text("UID"))
+	switch {
+	case task == nil:
+		task = ical.NewTask(todo)
+		s.store.Tasks = append(s.store.Tasks, task)
+		s.dirty = true
+		s.apply(task, todo)
+		s.result.Received++
+	case task.UpdatedAt.After(ical.LastModified(todo)):
+		return s.push(task, path, etag)
+	case s.apply(task, todo):
+		s.result.Received++
+	}
+	s.track(task, path, etag)
+	return nil
+}
+
+// push uploads a task, creating its object when etag is empty. An object
+// changed or created on the server in the meantime is left alone and the task
+// reported as a conflict; the next sync fetches it.
+func (s *syncer) push(task *model.Task, path, etag string) error {
+	todo := ical.Todo(task, s.store.GetProjectNames(task.ProjectIDs), time.Now())
+	newETag, err := s.client.Put(path, todo, etag)
+	if errors.Is(err, ErrPrecondition) {
+		if !slices.Contains(s.result.Conflicts, task.Name) {
+			s.result.Conflicts = append(s.result.Conflicts, task.Name)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.track(task, path, newETag)
+	s.result.Sent++
+	return nil
+}
+
+func (s *syncer) track(task *model.Task, path, etag string) {
+	s.state.Items[task.ID] = ItemState{Path: path, ETag: etag, Updated: task.UpdatedAt}
+}
+
+// apply updates a task from its VTODO and reports whether anything changed.
+func (s *syncer) apply(task *model.Task, todo *ical.Component) bool {
+	before := task.UpdatedAt
+
+	projectIDs := s.projectIDs(ical.ApplyTodo(task, todo))
+	current := slices.Clone(task.ProjectIDs)
+	slices.Sort(current)
+	if !slices.Equal(current, slices.Sorted(slices.Values(projectIDs))) {
+		task.SetProjects(projectIDs)
+	}
+
+	if task.UpdatedAt.Equal(before) {
+		return false
+	}
+	s.dirty = true
+	return true
+}
+
+// projectIDs resolves project names, creating the projects that don't exist
+// yet.
+func (s *syncer) projectIDs(names []string) []string {
+	ids := []string{}
+	for _, name := range names {
+		project := s.store.ProjectByName(name)
+		if project == nil {
+			project = model.NewProject(name)
+			s.store.Projects = append(s.store.Projects, project)
+			s.dirty = true
+		}
+		ids = append(ids, project.ID)
+	}
+	return ids
+}
@@ -0,0 +1,308 @@
+package caldav
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"t7t/internal/ical"
+	"t7t/internal/model"
+)
+
+func newStore(t *testing.T, names ...string) *model.Store {
+	t.Helper()
+	store, err := model.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if err := store.AddTask(model.NewTask(name, "", model.CategoryToday)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func runSync(t *testing.T, store *model.Store, f *fakeCollection, state *State) Result {
+	t.Helper()
+	result, err := Sync(store, f.client(), state)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	return result
+}
+
+func newState() *State {
+	return &State{Items: make(map[string]ItemState)}
+}
+
+// editOnServer changes a task's object the way another client would, marking
+// it modified at the given time.
+func editOnServer(f *fakeCollection, path, summary string, modified time.Time) {
+	todo := f.todo(path)
+	todo.SetText("SUMMARY", summary)
+	todo.SetTime("LAST-MODIFIED", modified)
+	f.put(path, todo)
+}
+
+func TestSyncPushesNewTasks(t *testing.T) {
+	f := newFakeCollection(t)
+	store := newStore(t, "Write report", "Call mom")
+	state := newState()
+
+	result := runSync(t, store, f, state)
+	if result.Sent != 2 || f.len() != 2 {
+		t.Fatalf("sent %d, server has %d objects, want 2 and 2", result.Sent, f.len())
+	}
+	for _, task := range store.Tasks {
+		item, ok := state.Items[task.ID]
+		if !ok {
+			t.Fatalf("task %q not tracked", task.Name)
+		}
+		if got := f.todo(item.Path).Text("SUMMARY"); got != task.Name {
+			t.Errorf("server summary %q, want %q", got, task.Name)
+		}
+	}
+
+	if result := runSync(t, store, f, state); result.Sent+result.Received+result.DeletedHere+result.DeletedThere != 0 || len(result.Conflicts) != 0 {
+		t.Errorf("second sync did %+v, want nothing", result)
+	}
+}
+
+func TestSyncPullsNewObjects(t *testing.T) {
+	f := newFakeCollection(t)
+	todo := ical.New("VTODO")
+	todo.SetText("UID", "phone-1")
+	todo.SetText("SUMMARY", "Buy milk")
+	todo.SetList("CATEGORIES", []string{"Errands"})
+	f.put(collectionPath+"phone-1.ics", todo)
+
+	event := ical.New("VEVENT")
+	event.SetText("UID", "meeting")
+	f.put(collectionPath+"meeting.ics", event)
+
+	store := newStore(t)
+	state := newState()
+	result := runSync(t, store, f, state)
+	if result.Received != 1 {
+		t.Fatalf("received %d, want 1", result.Received)
+	}
+	task := store.GetTask("phone-1")
+	if task == nil || task.Name != "Buy milk" {
+		t.Fatalf("pulled task %+v, want Buy milk", task)
+	}
+	if names := store.GetProjectNames(task.ProjectIDs); len(names) != 1 || names[0] != "Errands" {
+		t.Errorf("projects %v, want [Errands]", names)
+	}
+	if _, ok := state.Skipped[collectionPath+"meeting.ics"]; !ok {
+		t.Error("event not remembered as skipped")
+	}
+}
+
+func TestSyncPullsServerChanges(t *testing.T) {
+	f := newFakeCollection(t)
+	store := newStore(t, "Write report")
+	state := newState()
+	runSync(t, store, f, state)
+
+	task := store.Tasks[0]
+	editOnServer(f, state.Items[task.ID].Path, "Write final report", time.Now().Add(time.Minute))
+
+	result := runSync(t, store, f, state)
+	if result.Received != 1 || task.Name != "Write final report" {
+		t.Fatalf("received %d, name %q, want 1 and the server's name", result.Received, task.Name)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Errorf("conflicts %v, want none", result.Conflicts)
+	}
+}
+
+func TestSyncPushesLocalChanges(t *testing.T) {
+	f := newFakeCollection(t)
+	store := newStore(t, "Write report")
+	state := newState()
+	runSync(t, store, f, state)
+
+	task := store.Tasks[0]
+	task.Update("Write final report", "")
+	result := runSync(t, store, f, state)
+	if result.Sent != 1 {
+		t.Fatalf("sent %d, want 1", result.Sent)
+	}
+	if got := f.todo(state.Items[task.ID].Path).Text("SUMMARY"); got != "Write final report" {
+		t.Errorf("server summary %q, want the local name", got)
+	}
+}
+
+func TestSyncConflictKeepsNewer(t *testing.T) {
+	tests := []struct {
+		name     string
+		offset   time.Duration // of the server's change from the local one
+		want     string
+		wantSent int
+	}{
+		{"server newer", time.Hour, "Server name", 0},
+		{"local newer", -time.Hour, "Local name", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeCollection(t)
+			store := newStore(t, "Original")
+			state := newState()
+			runSync(t, store, f, state)
+
+			task := store.Tasks[0]
+			path := state.Items[task.ID].Path
+			task.Update("Local name", "")
+			editOnServer(f, path, "Server name", task.UpdatedAt.Add(tt.offset))
+
+			result := runSync(t, store, f, state)
+			if len(result.Conflicts) != 1 {
+				t.Fatalf("conflicts %v, want one", result.Conflicts)
+			}
+			if result.Sent != tt.wantSent {
+				t.Errorf("sent %d, want %d", result.Sent, tt.wantSent)
+			}
+			if task.Name != tt.want {
+				t.Errorf("local name %q, want %q", task.Name, tt.want)
+			}
+			if got := f.todo(path).Text("SUMMARY"); got != tt.want {
+				t.Errorf("server name %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncDeletesOnServer(t *testing.T) {
+	f := newFakeCollection(t)
+	store := newStore(t, "Write report")
+	state := newState()
+	runSync(t, store, f, state)
+
+	id := store.Tasks[0].ID
+	if err := store.DeleteTask(id); err != nil {
+		t.Fatal(err)
+	}
+	result := runSync(t, store, f, state)
+	if result.DeletedThere != 1 || f.len() != 0 {
+		t.Fatalf("deleted there %d, server has %d objects, want 1 and 0", result.DeletedThere, f.len())
+	}
+	if _, ok := state.Items[id]; ok {
+		t.Error("deleted task still tracked")
+	}
+}
+
+func TestSyncDeletesHere(t *testing.T) {
+	f := newFakeCollection(t)
+	store := newStore(t, "Write report")
+	state := newState()
+	runSync(t, store, f, state)
+
+	id := store.Tasks[0].ID
+	f.remove(state.Items[id].Path)
+	result := runSync(t, store, f, state)
+	if result.DeletedHere != 1 || store.GetTask(id) != nil {
+		t.Fatalf("deleted here %d, task still here: %v", result.DeletedHere, store.GetTask(id) != nil)
+	}
+}
+
+func TestSyncKeepsTaskDeletedOnServerButChangedHere(t *testing.T) {
+	f := newFakeCollection(t)
+	store := newStore(t, "Write report")
+	state := newState()
+	runSync(t, store, f, state)
+
+	task := store.Tasks[0]
+	f.remove(state.Items[task.ID].Path)
+	task.Update("Write final report", "")
+	result := runSync(t, store, f, state)
+	if store.GetTask(task.ID) == nil || result.Sent != 1 || f.len() != 1 {
+		t.Fatalf("result %+v, server has %d objects; want the task kept and sent again", result, f.len())
+	}
+}
+
+func TestSyncLeavesObjectChangedDuringPut(t *testing.T) {
+	f := newFakeCollection(t)
+	store := newStore(t, "Write report")
+	state := newState()
+	runSync(t, store, f, state)
+
+	task := store.Tasks[0]
+	path := state.Items[task.ID].Path
+	task.Update("Local name", "")
+	// Another client saves between the listing and the upload.
+	f.beforeWrite = func() {
+		editOnServer(f, path, "Server name", task.UpdatedAt.Add(time.Hour))
+	}
+
+	result := runSync(t, store, f, state)
+	if result.Sent != 0 || len(result.Conflicts) != 1 {
+		t.Errorf("sent %d, conflicts %v; want the upload refused as a conflict", result.Sent, result.Conflicts)
+	}
+	if got := f.todo(path).Text("SUMMARY"); got != "Server name" {
+		t.Fatalf("server summary %q, want the other client's change kept", got)
+	}
+
+	// The next sync sees the change and resolves it.
+	result = runSync(t, store, f, state)
+	if task.Name != "Server name" || len(result.Conflicts) != 1 {
+		t.Errorf("name %q, conflicts %v; want the newer server name as a conflict", task.Name, result.Conflicts)
+	}
+}
+
+func TestSyncLeavesObjectCreatedDuringPut(t *testing.T) {
+	f := newFakeCollection(t)
+	store := newStore(t, "Write report")
+	state := newState()
+
+	task := store.Tasks[0]
+	path := f.client().path(task.ID)
+	// Another client uploads the same task between the listing and the upload.
+	f.beforeWrite = func() {
+		other := *task
+		other.Name = "Server name"
+		f.put(path, ical.Todo(&other, nil, task.UpdatedAt.Add(time.Hour)))
+	}
+
+	result := runSync(t, store, f, state)
+	if result.Sent != 0 || len(result.Conflicts) != 1 {
+		t.Errorf("sent %d, conflicts %v; want the creation refused as a conflict", result.Sent, result.Conflicts)
+	}
+	if got := f.todo(path).Text("SUMMARY"); got != "Server name" {
+		t.Fatalf("server summary %q, want the other client's object kept", got)
+	}
+}
+
+func TestClientPreconditions(t *testing.T) {
+	f := newFakeCollection(t)
+	c := f.client()
+	todo := ical.New("VTODO")
+	todo.SetText("UID", "a")
+	todo.SetText("SUMMARY", "A")
+	path := collectionPath + "a.ics"
+
+	etag, err := c.Put(path, todo, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Put(path, todo, ""); !errors.Is(err, ErrPrecondition) {
+		t.Errorf("creating an existing object: %v, want ErrPrecondition", err)
+	}
+
+	newETag, err := c.Put(path, todo, etag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Put(path, todo, etag); !errors.Is(err, ErrPrecondition) {
+		t.Errorf("replacing with a stale ETag: %v, want ErrPrecondition", err)
+	}
+	if err := c.Delete(path, etag); !errors.Is(err, ErrPrecondition) {
+		t.Errorf("deleting with a stale ETag: %v, want ErrPrecondition", err)
+	}
+	if err := c.Delete(path, newETag); err != nil {
+		t.Errorf("deleting with the current ETag: %v", err)
+	}
+	if err := c.Delete(path, newETag); err != nil {
+		t.Errorf("deleting a missing object: %v, want it counted as deleted", err)
+	}
+}
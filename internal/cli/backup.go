@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+func runBackup(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	if len(args) == 0 {
+		return missingArgument("list|restore")
+	}
+
+	switch args[0] {
+	case "list":
+		backups, err := store.Backups()
+		if err != nil {
+			return err
+		}
+		if len(backups) == 0 {
+			fmt.Fprint(os.Stdout, m.CLIBackupNone)
+			return nil
+		}
+		for _, b := range backups {
+			fmt.Fprintf(os.Stdout, "%s  %s  %s\n", b.Name, b.Time.Format("2006-01-02 15:04"),
+				fmt.Sprintf(m.BackupCounts, b.Tasks, b.Open, b.Projects))
+		}
+		return nil
+
+	case "restore":
+		if len(args) < 2 {
+			return missingArgument("snapshot")
+		}
+		backup, err := store.FindBackup(args[1])
+		if errors.Is(err, model.ErrBackupNotFound) {
+			return fmt.Errorf(m.CLIBackupNotFound, args[1])
+		}
+		if err != nil {
+			return err
+		}
+		if err := store.RestoreBackup(backup); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, m.CLIBackupRestored, backup.Name, fmt.Sprintf(m.BackupCounts, backup.Tasks, backup.Open, backup.Projects))
+		return nil
+	}
+
+	return fmt.Errorf(m.CLIUnknownCommand, "backup "+args[0])
+}
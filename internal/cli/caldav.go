@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"t7t/internal/caldav"
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+func runCalDAV(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	fs := flag.NewFlagSet("caldav", flag.ContinueOnError)
+	url := fs.String("url", "", "")
+	user := fs.String("user", "", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	state, err := caldav.LoadState(store.Dir())
+	if err != nil {
+		return err
+	}
+	if *url != "" {
+		state.SetURL(*url)
+	}
+	if *user != "" {
+		state.Username = *user
+	}
+	if state.URL == "" {
+		return errors.New(m.CLICalDAVNoURL)
+	}
+
+	var password string
+	if state.Username != "" {
+		if password, err = readSecret("T7T_CALDAV_PASSWORD", m.CLICalDAVPasswordPrompt); err != nil {
+			return err
+		}
+	}
+	client, err := caldav.NewClient(state.URL, state.Username, password)
+	if err != nil {
+		return err
+	}
+
+	result, err := caldav.Sync(store, client, state)
+	if saveErr := state.Save(store.Dir()); err == nil {
+		err = saveErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, m.CLICalDAVResult, result.Sent, result.Received, result.DeletedHere, result.DeletedThere)
+	if len(result.Conflicts) > 0 {
+		fmt.Fprintf(os.Stdout, m.CLICalDAVConflicts, strings.Join(result.Conflicts, ", "))
+	}
+	return nil
+}
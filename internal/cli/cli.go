@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+// Run executes a command line subcommand against the store.
+func Run(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	if len(args) == 0 {
+		fmt.Fprint(os.Stdout, m.CLIUsage)
+		return nil
+	}
+
+	switch args[0] {
+	case "add":
+		return runAdd(store, args[1:])
+	case "capture":
+		return runCapture(store, args[1:])
+	case "list":
+		return runList(store, args[1:])
+	case "daemon":
+		return runDaemon(store)
+	case "timer":
+		return runTimer(store, args[1:])
+	case "backup":
+		return runBackup(store, args[1:])
+	case "encrypt":
+		return runEncrypt(store)
+	case "decrypt":
+		return runDecrypt(store)
+	case "sync":
+		return runSync(store, args[1:])
+	case "caldav":
+		return runCalDAV(store, args[1:])
+	case "merge":
+		return runMerge(store, args[1:])
+	case "serve":
+		return runServe(store, args[1:])
+	case "import":
+		return runImport(store, args[1:])
+	case "export":
+		return runExport(store, args[1:])
+	case "report":
+		return runReport(store, args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, m.CLIUsage)
+		return nil
+	}
+
+	fmt.Fprint(os.Stderr, m.CLIUsage)
+	return fmt.Errorf(m.CLIUnknownCommand, args[0])
+}
+
+// findTask resolves a task reference given on the command line to exactly
+// one task.
+func findTask(store *model.Store, ref string) (*model.Task, error) {
+	m := i18n.Get()
+	matches := store.FindTasks(ref)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf(m.CLITaskNotFound, ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf(m.CLITaskAmbiguous, len(matches), ref)
+	}
+}
+
+func missingArgument(name string) error {
+	return fmt.Errorf(i18n.Get().CLIMissingArgument, name)
+}
+
+// parseFileArgs parses flags around the single file a command takes, so
+// flags given after the file, like `import todotxt tasks.txt -dry-run`,
+// still apply. Any other argument is an error rather than ignored.
+func parseFileArgs(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() == 0 {
+		return "", missingArgument("file")
+	}
+	file := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return "", err
+	}
+	if fs.NArg() > 0 {
+		return "", fmt.Errorf(i18n.Get().CLIExtraArgument, fs.Arg(0))
+	}
+	return file, nil
+}
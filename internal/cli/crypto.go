@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+
+	"golang.org/x/term"
+)
+
+const unlockAttempts = 3
+
+// stdin is shared so consecutive prompts can read lines from the same pipe.
+var stdin = bufio.NewReader(os.Stdin)
+
+// readPassphrase asks for a passphrase without echoing it. $T7T_PASSPHRASE
+// takes precedence so scripts can run unattended, and piped input is read as
+// a single line.
+func readPassphrase(prompt string) (string, error) {
+	return readSecret("T7T_PASSPHRASE", prompt)
+}
+
+// readSecret reads a secret from the environment variable env, or else
+// prompts for it like readPassphrase.
+func readSecret(env, prompt string) (string, error) {
+	if secret := os.Getenv(env); secret != "" {
+		return secret, nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		pass, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(pass), err
+	}
+
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// Unlock prompts for the passphrase of an encrypted store, giving a few
+// attempts when running interactively.
+func Unlock(store *model.Store) error {
+	m := i18n.Get()
+	attempts := unlockAttempts
+	if os.Getenv("T7T_PASSPHRASE") != "" || !term.IsTerminal(int(os.Stdin.Fd())) {
+		attempts = 1
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		var pass string
+		if pass, err = readPassphrase(m.CLIPassphrasePrompt); err != nil {
+			return err
+		}
+		if err = store.Unlock(pass); !errors.Is(err, model.ErrWrongPassphrase) {
+			return err
+		}
+		if i < attempts-1 {
+			fmt.Fprintln(os.Stderr, m.CLIWrongPassphrase)
+		}
+	}
+	return errors.New(m.CLIWrongPassphrase)
+}
+
+func runEncrypt(store *model.Store) error {
+	m := i18n.Get()
+	if store.Encrypted() {
+		return errors.New(m.CLIAlreadyEncrypted)
+	}
+
+	pass, err := readPassphrase(m.CLINewPassphrase)
+	if err != nil {
+		return err
+	}
+	if pass == "" {
+		return errors.New(m.CLIEmptyPassphrase)
+	}
+	if os.Getenv("T7T_PASSPHRASE") == "" {
+		confirm, err := readPassphrase(m.CLIConfirmPassphrase)
+		if err != nil {
+			return err
+		}
+		if confirm != pass {
+			return errors.New(m.CLIPassphraseMismatch)
+		}
+	}
+
+	if err := store.Encrypt(pass); err != nil {
+		return err
+	}
+	fmt.Fprint(os.Stdout, m.CLIEncrypted)
+	return nil
+}
+
+func runDecrypt(store *model.Store) error {
+	m := i18n.Get()
+	if !store.Encrypted() {
+		return errors.New(m.CLINotEncrypted)
+	}
+	if err := store.Decrypt(); err != nil {
+		return err
+	}
+	fmt.Fprint(os.Stdout, m.CLIDecrypted)
+	return nil
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"t7t/internal/daemon"
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+func runDaemon(store *model.Store) error {
+	m := i18n.Get()
+
+	path := daemon.SocketPath(store.Dir())
+	if daemon.Running(store.Dir()) {
+		return fmt.Errorf(m.CLIDaemonRunning, path)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stdout, m.CLIDaemonListening, path)
+	d := daemon.New(store)
+	d.Warn = func(err error) {
+		fmt.Fprintf(os.Stderr, m.WarningSaveHook, err)
+	}
+	err := d.Serve(ctx)
+	if errors.Is(err, daemon.ErrRunning) {
+		return fmt.Errorf(m.CLIDaemonRunning, path)
+	}
+	return err
+}
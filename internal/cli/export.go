@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"t7t/internal/i18n"
+	"t7t/internal/ical"
+	"t7t/internal/markdown"
+	"t7t/internal/model"
+	"t7t/internal/org"
+	"t7t/internal/taskcsv"
+	"t7t/internal/taskwarrior"
+	"t7t/internal/todotxt"
+)
+
+func runExport(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	if len(args) == 0 {
+		return missingArgument("format")
+	}
+	format := args[0]
+
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("o", "", "")
+	by := fs.String("by", "category", "")
+	sep := fs.String("sep", ",", "")
+	events := fs.Bool("events", false, "")
+	auto := fs.Bool("auto", false, "")
+	plaintext := fs.Bool("plaintext", false, "")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	var export func(*model.Store, io.Writer) error
+	switch format {
+	case "todotxt":
+		export = todotxt.Export
+	case "taskwarrior":
+		export = taskwarrior.Export
+	case "org":
+		export = org.Export
+	case "markdown":
+		grouping, ok := markdown.ParseGrouping(*by)
+		if !ok {
+			return fmt.Errorf(m.CLIUnknownGrouping, *by)
+		}
+		export = func(store *model.Store, w io.Writer) error {
+			return markdown.Export(store, w, grouping)
+		}
+	case "csv":
+		comma := []rune(*sep)
+		if len(comma) != 1 {
+			return fmt.Errorf(m.CLIBadSeparator, *sep)
+		}
+		export = func(store *model.Store, w io.Writer) error {
+			return taskcsv.Export(store, w, comma[0])
+		}
+	case "ics":
+		if flagSet(fs, "auto") {
+			return setFeed(store, *output, *events, *auto, *plaintext)
+		}
+		export = func(store *model.Store, w io.Writer) error {
+			return ical.Export(store, w, *events)
+		}
+	default:
+		return fmt.Errorf(m.CLIUnknownFormat, format)
+	}
+
+	if *output == "" {
+		return export(store, os.Stdout)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := export(store, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// setFeed turns the calendar file kept up to date on every save on or off.
+// For encrypted data it takes plaintext to agree to the file not being
+// encrypted.
+func setFeed(store *model.Store, output string, events, on, plaintext bool) error {
+	m := i18n.Get()
+	if !on {
+		if err := ical.SaveFeed(store.Dir(), nil); err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stdout, m.CLIFeedDisabled)
+		return nil
+	}
+
+	if output == "" {
+		return missingArgument("-o")
+	}
+	if store.Encrypted() && !plaintext {
+		return errors.New(m.CLIFeedEncrypted)
+	}
+	path, err := filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+	feed := &ical.Feed{Path: path, Events: events, Plaintext: plaintext}
+	if err := feed.Write(store); err != nil {
+		return err
+	}
+	if err := ical.SaveFeed(store.Dir(), feed); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, m.CLIFeedEnabled, path)
+	return nil
+}
+
+// flagSet reports whether a flag was given, even if set to its default.
+func flagSet(fs *flag.FlagSet, name string) bool {
+	found := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"t7t/internal/i18n"
+	"t7t/internal/markdown"
+	"t7t/internal/model"
+	"t7t/internal/org"
+	"t7t/internal/taskcsv"
+	"t7t/internal/taskwarrior"
+	"t7t/internal/todotxt"
+)
+
+func runImport(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	if len(args) == 0 {
+		return missingArgument("format")
+	}
+	format := args[0]
+
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "")
+	mapping := fs.String("map", "", "")
+	aliases := fs.String("alias", "", "")
+	sep := fs.String("sep", ",", "")
+	file, err := parseFileArgs(fs, args[1:])
+	if err != nil {
+		return err
+	}
+
+	var parse func(*model.Store, io.Reader) (*model.Batch, error)
+	switch format {
+	case "todotxt":
+		parse = todotxt.Parse
+	case "markdown":
+		parse = markdown.Parse
+	case "taskwarrior":
+		parse = taskwarrior.Parse
+	case "org":
+		parse = org.Parse
+	case "csv":
+		opts, err := csvOptions(*mapping, *aliases, *sep)
+		if err != nil {
+			return err
+		}
+		parse = func(store *model.Store, r io.Reader) (*model.Batch, error) {
+			return taskcsv.Parse(store, r, opts)
+		}
+	default:
+		return fmt.Errorf(m.CLIUnknownFormat, format)
+	}
+
+	r, err := openInput(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	batch, err := parse(store, r)
+	var rowErrors taskcsv.RowErrors
+	if errors.As(err, &rowErrors) {
+		for _, e := range rowErrors {
+			fmt.Fprintf(os.Stderr, m.CLIImportLineError, e.Line, e.Err)
+		}
+		return fmt.Errorf(m.CLIImportInvalidRows, len(rowErrors))
+	}
+	if err != nil {
+		return err
+	}
+	printBatch(store, batch)
+
+	if *dryRun {
+		fmt.Fprintf(os.Stdout, m.CLIImportDryRun, len(batch.Tasks), len(batch.Projects))
+		if len(batch.Updates) > 0 {
+			fmt.Fprintf(os.Stdout, m.CLIImportDryRunUpdates, len(batch.Updates))
+		}
+		return nil
+	}
+	if len(batch.Tasks) > 0 || len(batch.Updates) > 0 {
+		if err := store.AddBatch(batch); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stdout, m.CLIImported, len(batch.Tasks), len(batch.Projects))
+	if len(batch.Updates) > 0 {
+		fmt.Fprintf(os.Stdout, m.CLIImportUpdated, len(batch.Updates))
+	}
+	return nil
+}
+
+// printBatch shows everything an import creates.
+func printBatch(store *model.Store, batch *model.Batch) {
+	m := i18n.Get()
+	for _, p := range batch.Projects {
+		fmt.Fprintf(os.Stdout, m.CLIImportProject, p.Name)
+	}
+	for _, t := range batch.Updates {
+		fmt.Fprintf(os.Stdout, "~ %s %-14s %s\n", checkbox(t), t.Category.String(), t.Name)
+		if existing := store.GetTask(t.ID); existing != nil {
+			fmt.Fprintf(os.Stdout, m.CLIImportChanges, strings.Join(model.Changes(existing, t), ", "))
+		}
+	}
+	for _, t := range batch.Tasks {
+		fmt.Fprintf(os.Stdout, "+ %s %-14s %s\n", checkbox(t), t.Category.String(), t.Name)
+		if names := batch.ProjectNames(store, t); len(names) > 0 {
+			fmt.Fprintf(os.Stdout, m.CLIImportTaskProjects, strings.Join(names, ", "))
+		}
+		if t.Description != "" {
+			for _, line := range strings.Split(t.Description, "\n") {
+				fmt.Fprintln(os.Stdout, strings.TrimRight("      "+line, " "))
+			}
+		}
+	}
+}
+
+func checkbox(t *model.Task) string {
+	if t.Completed {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// csvOptions reads the CSV import flags: -map field=column,... and
+// -alias name=category,... pairs and the field separator.
+func csvOptions(mapping, aliases, sep string) (taskcsv.Options, error) {
+	m := i18n.Get()
+	opts := taskcsv.Options{}
+
+	comma := []rune(sep)
+	if len(comma) != 1 {
+		return opts, fmt.Errorf(m.CLIBadSeparator, sep)
+	}
+	opts.Comma = comma[0]
+
+	var err error
+	if opts.Mapping, err = parsePairs(mapping); err != nil {
+		return opts, err
+	}
+	names, err := parsePairs(aliases)
+	if err != nil {
+		return opts, err
+	}
+	opts.Aliases = make(map[string]model.Category, len(names))
+	for name, value := range names {
+		category, ok := model.ParseCategory(value)
+		if !ok {
+			return opts, fmt.Errorf(m.CLIUnknownCategory, value)
+		}
+		opts.Aliases[name] = category
+	}
+	return opts, nil
+}
+
+// parsePairs splits a comma separated list of key=value pairs.
+func parsePairs(value string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return pairs, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf(i18n.Get().CLIBadPair, pair)
+		}
+		pairs[k] = v
+	}
+	return pairs, nil
+}
+
+// openInput opens a file given on the command line, where "-" stands for
+// standard input, read through the reader shared with the passphrase prompt.
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(stdin), nil
+	}
+	return os.Open(path)
+}
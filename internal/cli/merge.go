@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+func runMerge(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "")
+	file, err := parseFileArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	other, err := store.ParseVersion(data)
+	if err != nil {
+		return err
+	}
+
+	report, err := store.Merge(other, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	formats := map[model.MergeAction]string{
+		model.MergeAdded:       m.CLIMergeAdded,
+		model.MergeKept:        m.CLIMergeKept,
+		model.MergeRemoved:     m.CLIMergeRemoved,
+		model.MergeSurvived:    m.CLIMergeSurvived,
+		model.MergeStayDeleted: m.CLIMergeStayDeleted,
+		model.MergeRestored:    m.CLIMergeRestored,
+		model.MergeTookField:   m.CLIMergeTookField,
+		model.MergeKeptField:   m.CLIMergeKeptField,
+	}
+	for _, d := range report.Decisions {
+		kind := m.MergeTask
+		if d.Project {
+			kind = m.MergeProject
+		}
+		when := d.Time.Local().Format("2006-01-02 15:04")
+		if d.Field != "" {
+			fmt.Fprintf(os.Stdout, formats[d.Action], kind, d.Name, d.Field, when)
+		} else {
+			fmt.Fprintf(os.Stdout, formats[d.Action], kind, d.Name, when)
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, m.CLIMergeSummary, report.Changes(), report.Identical)
+	if *dryRun {
+		fmt.Fprint(os.Stdout, m.CLIMergeDryRun)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+	"t7t/internal/report"
+)
+
+func runReport(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	if len(args) == 0 {
+		return missingArgument("format")
+	}
+	if args[0] != "html" {
+		return fmt.Errorf(m.CLIUnknownFormat, args[0])
+	}
+
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	output := fs.String("o", "", "")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return report.HTML(store, os.Stdout, time.Now())
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := report.HTML(store, f, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+	"t7t/internal/server"
+)
+
+func runServe(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:7777", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	token := os.Getenv("T7T_API_TOKEN")
+	if token == "" {
+		var err error
+		if token, err = server.LoadToken(store.Dir()); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, m.CLIServeToken, server.TokenPath(store.Dir()))
+	} else {
+		fmt.Fprint(os.Stdout, m.CLIServeTokenEnv)
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(store, token).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(os.Stdout, m.CLIServeListening, *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"t7t/internal/gitsync"
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+func runSync(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	remote := fs.String("remote", "", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	result, err := gitsync.Sync(store, *remote)
+	if err != nil {
+		return err
+	}
+
+	if result.Committed != "" {
+		fmt.Fprintf(os.Stdout, m.CLISyncCommitted, result.Committed)
+	}
+	switch {
+	case result.FastForward:
+		fmt.Fprint(os.Stdout, m.CLISyncPulled)
+	case result.Merged:
+		fmt.Fprint(os.Stdout, m.CLISyncMerged)
+		if len(result.Conflicts) > 0 {
+			fmt.Fprintf(os.Stdout, m.CLISyncConflicts, strings.Join(result.Conflicts, ", "))
+		}
+	}
+	if result.Pushed {
+		fmt.Fprint(os.Stdout, m.CLISyncPushed)
+	} else {
+		fmt.Fprint(os.Stdout, m.CLISyncNoRemote)
+	}
+	return nil
+}
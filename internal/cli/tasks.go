@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"t7t/internal/daemon"
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+// categoryFlag validates a -category value; empty means none was given.
+func categoryFlag(value string) (model.Category, error) {
+	category := model.Category(value)
+	if category != "" && !category.Valid() {
+		return "", fmt.Errorf(i18n.Get().CLIUnknownCategory, value)
+	}
+	return category, nil
+}
+
+// runAdd adds a task, through the daemon when one is running.
+func runAdd(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	categoryName := fs.String("category", string(model.CategoryToday), "")
+	description := fs.String("desc", "", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	name := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if name == "" {
+		return missingArgument("name")
+	}
+	category, err := categoryFlag(*categoryName)
+	if err != nil {
+		return err
+	}
+	if name == "-" {
+		return capture(store, category)
+	}
+
+	tasks := daemon.Open(store)
+	defer tasks.Close()
+
+	task, err := tasks.Add(daemon.AddArgs{Name: name, Description: *description, Category: category})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, m.CLITaskAdded, task.Name, task.ID)
+	return nil
+}
+
+// runCapture adds a task for each line of standard input.
+func runCapture(store *model.Store, args []string) error {
+	fs := flag.NewFlagSet("capture", flag.ContinueOnError)
+	categoryName := fs.String("category", string(model.CategoryToday), "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	category, err := categoryFlag(*categoryName)
+	if err != nil {
+		return err
+	}
+	return capture(store, category)
+}
+
+// capture reads one task per line from standard input in quick-add syntax
+// (!week, +project, due:fri) and adds them all in one save, through the
+// daemon when one is running. Lines without a category go to category.
+func capture(store *model.Store, category model.Category) error {
+	m := i18n.Get()
+
+	var lines []string
+	// Read through the shared reader, which may already hold the lines
+	// piped after the passphrase.
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return missingArgument("name")
+	}
+
+	tasks := daemon.Open(store)
+	defer tasks.Close()
+
+	added, err := tasks.Capture(daemon.CaptureArgs{Lines: lines, Category: category})
+	if err != nil {
+		return err
+	}
+	for _, task := range added {
+		fmt.Fprintf(os.Stdout, m.CLITaskAdded, task.Name, task.ID)
+	}
+	return nil
+}
+
+// runList prints the open tasks, through the daemon when one is running.
+func runList(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	categoryName := fs.String("category", "", "")
+	all := fs.Bool("all", false, "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	category, err := categoryFlag(*categoryName)
+	if err != nil {
+		return err
+	}
+
+	tasks := daemon.Open(store)
+	defer tasks.Close()
+
+	list, err := tasks.List(daemon.ListArgs{Category: category, All: *all})
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Fprint(os.Stdout, m.CLIListEmpty)
+		return nil
+	}
+	for _, t := range list {
+		check := "[ ]"
+		if t.Completed {
+			check = "[x]"
+		}
+		fmt.Fprintf(os.Stdout, "%s %s  %-14s %s\n", shortID(t.ID), check, t.Category.String(), t.Name)
+	}
+	return nil
+}
+
+// shortID abbreviates a task ID for listings. IDs from other tools, such as
+// CalDAV UIDs, can be shorter than the UUIDs t7t makes.
+func shortID(id string) string {
+	return id[:min(8, len(id))]
+}
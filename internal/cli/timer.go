@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+func runTimer(store *model.Store, args []string) error {
+	m := i18n.Get()
+
+	if len(args) == 0 {
+		return missingArgument("start|stop|status|report")
+	}
+
+	switch args[0] {
+	case "start":
+		if len(args) < 2 {
+			return missingArgument("task")
+		}
+		task, err := findTask(store, strings.Join(args[1:], " "))
+		if err != nil {
+			return err
+		}
+		if err := store.StartTimer(task); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, m.CLITimerStarted, task.Name)
+		return nil
+
+	case "stop":
+		task, elapsed, err := store.StopTimer()
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			fmt.Fprint(os.Stdout, m.CLITimerNone)
+			return nil
+		}
+		fmt.Fprintf(os.Stdout, m.CLITimerStopped, task.Name, model.FormatDuration(elapsed))
+		return nil
+
+	case "status":
+		task := store.RunningTimer()
+		if task == nil {
+			fmt.Fprint(os.Stdout, m.CLITimerNone)
+			return nil
+		}
+		elapsed := task.RunningEntry().Duration(time.Now())
+		fmt.Fprintf(os.Stdout, m.CLITimerRunning, task.Name, model.FormatClock(elapsed))
+		return nil
+
+	case "report":
+		fs := flag.NewFlagSet("timer report", flag.ContinueOnError)
+		days := fs.Int("days", 7, "")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return printTimeReport(store, *days)
+	}
+
+	return fmt.Errorf(m.CLIUnknownCommand, "timer "+args[0])
+}
+
+func printTimeReport(store *model.Store, days int) error {
+	m := i18n.Get()
+	now := time.Now()
+	y, mo, d := now.Date()
+	since := time.Date(y, mo, d, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -days+1)
+
+	report := store.TimeReport(since, now)
+	fmt.Fprintf(os.Stdout, m.CLIReportTitle, days, model.FormatDuration(report.Total))
+
+	taskName := func(id string) string {
+		if task := store.GetTask(id); task != nil {
+			return task.Name
+		}
+		return id
+	}
+	projectName := func(name string) string {
+		if name == "" {
+			return m.CLIReportNoProject
+		}
+		return name
+	}
+	sections := []struct {
+		title  string
+		totals []model.TimeTotal
+		label  func(string) string
+	}{
+		{m.CLIReportByTask, report.ByTask, taskName},
+		{m.CLIReportByProject, report.ByProject, projectName},
+		{m.CLIReportByDay, report.ByDay, func(day string) string { return day }},
+	}
+	for _, section := range sections {
+		if len(section.totals) == 0 {
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "\n%s\n", section.title)
+		for _, t := range section.totals {
+			fmt.Fprintf(os.Stdout, "  %8s  %s\n", model.FormatDuration(t.Duration), section.label(t.Label))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultWorkspace is the workspace stored directly in the data directory.
+const DefaultWorkspace = "default"
+
+var ErrInvalidWorkspace = errors.New("invalid workspace name")
+
+var dataDirOverride string
+
+// SetDataDir overrides the data directory, as done by the --data-dir flag.
+// Configuration is kept in the same directory.
+func SetDataDir(dir string) {
+	dataDirOverride = dir
+}
+
+// DataDir returns where task data lives. In order of precedence: the
+// --data-dir flag, $T7T_HOME, an existing ~/.t7t, $XDG_DATA_HOME/t7t and
+// finally ~/.t7t.
+func DataDir() (string, error) {
+	return resolveDir("XDG_DATA_HOME")
+}
+
+// ConfigDir returns where preferences such as the language live, following
+// the same rules as DataDir with $XDG_CONFIG_HOME in place of $XDG_DATA_HOME.
+func ConfigDir() (string, error) {
+	return resolveDir("XDG_CONFIG_HOME")
+}
+
+func resolveDir(xdgVar string) (string, error) {
+	if dataDirOverride != "" {
+		return dataDirOverride, nil
+	}
+	if dir := os.Getenv("T7T_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	legacy := filepath.Join(homeDir, ".t7t")
+
+	// Keep using ~/.t7t for existing installs so setting XDG variables
+	// later doesn't make the data disappear.
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy, nil
+	}
+	if xdg := os.Getenv(xdgVar); xdg != "" {
+		return filepath.Join(xdg, "t7t"), nil
+	}
+	return legacy, nil
+}
+
+// ValidWorkspace reports whether name can be used as a workspace directory.
+func ValidWorkspace(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		!strings.ContainsAny(name, `/\`) && strings.TrimSpace(name) == name
+}
+
+// WorkspaceDir returns the directory holding the given workspace's data. The
+// default workspace uses the data directory itself, others live under
+// workspaces/<name>.
+func WorkspaceDir(name string) (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	if name == "" || name == DefaultWorkspace {
+		return dataDir, nil
+	}
+	if !ValidWorkspace(name) {
+		return "", ErrInvalidWorkspace
+	}
+	return filepath.Join(dataDir, "workspaces", name), nil
+}
+
+// Workspaces lists the default workspace followed by the named ones in
+// alphabetical order.
+func Workspaces() ([]string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dataDir, "workspaces"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && ValidWorkspace(e.Name()) && e.Name() != DefaultWorkspace {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultWorkspace}, names...), nil
+}
@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"time"
+
+	"t7t/internal/model"
+)
+
+var (
+	ErrEmptyName       = errors.New("empty task name")
+	ErrUnknownCategory = errors.New("unknown category")
+)
+
+// Tasks is what the CLI needs from a store, whether it's opened directly or
+// served by a daemon.
+type Tasks interface {
+	Add(args AddArgs) (*model.Task, error)
+	Capture(args CaptureArgs) ([]*model.Task, error)
+	List(args ListArgs) ([]*model.Task, error)
+	Close() error
+}
+
+// Open connects to the daemon serving the store's directory, or falls back
+// to using the store directly when no daemon is running.
+func Open(store *model.Store) Tasks {
+	if client, err := Dial(store.Dir()); err == nil {
+		return client
+	}
+	return local{store}
+}
+
+// Client talks to a running daemon.
+type Client struct {
+	rpc *rpc.Client
+}
+
+// Dial connects to the daemon for the store in dir.
+func Dial(dir string) (*Client, error) {
+	conn, err := net.Dial("unix", SocketPath(dir))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{rpc: jsonrpc.NewClient(conn)}, nil
+}
+
+func (c *Client) Add(args AddArgs) (*model.Task, error) {
+	var task model.Task
+	if err := c.rpc.Call(serviceName+".Add", args, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (c *Client) Capture(args CaptureArgs) ([]*model.Task, error) {
+	var tasks []*model.Task
+	err := c.rpc.Call(serviceName+".Capture", args, &tasks)
+	return tasks, err
+}
+
+func (c *Client) List(args ListArgs) ([]*model.Task, error) {
+	var tasks []*model.Task
+	err := c.rpc.Call(serviceName+".List", args, &tasks)
+	return tasks, err
+}
+
+// Save hands a copy of the data to the daemon to merge and write, see
+// Service.Save. It fits model.Store.SaveVia: errors other than the daemon's
+// own answers mean it can't be reached and are reported as
+// model.ErrSaverGone.
+func (c *Client) Save(store *model.Store, settingsChanged bool) error {
+	args := SaveArgs{Data: store, SettingsChanged: settingsChanged}
+	err := c.rpc.Call(serviceName+".Save", args, &struct{}{})
+	var answer rpc.ServerError
+	if err != nil && !errors.As(err, &answer) {
+		return fmt.Errorf("%w: %v", model.ErrSaverGone, err)
+	}
+	return err
+}
+
+// Wait blocks until the daemon's store changes past version since, see
+// Service.Wait.
+func (c *Client) Wait(since uint64) (uint64, error) {
+	var version uint64
+	err := c.rpc.Call(serviceName+".Wait", WaitArgs{Since: since}, &version)
+	return version, err
+}
+
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
+
+type local struct {
+	store *model.Store
+}
+
+func (l local) Add(args AddArgs) (*model.Task, error) {
+	return addTask(l.store, args)
+}
+
+func (l local) Capture(args CaptureArgs) ([]*model.Task, error) {
+	return captureTasks(l.store, args)
+}
+
+func (l local) List(args ListArgs) ([]*model.Task, error) {
+	return listTasks(l.store, args), nil
+}
+
+func (l local) Close() error {
+	return nil
+}
+
+func addTask(store *model.Store, args AddArgs) (*model.Task, error) {
+	if args.Name == "" {
+		return nil, ErrEmptyName
+	}
+	if args.Category == "" {
+		args.Category = model.CategoryToday
+	}
+	if !args.Category.Valid() {
+		return nil, ErrUnknownCategory
+	}
+	task := model.NewTask(args.Name, args.Description, args.Category)
+	return task, store.AddTask(task)
+}
+
+// captureTasks adds a task for each line that names one, saving them all at
+// once, along with the projects they create.
+func captureTasks(store *model.Store, args CaptureArgs) ([]*model.Task, error) {
+	if args.Category == "" {
+		args.Category = model.CategoryToday
+	}
+	if !args.Category.Valid() {
+		return nil, ErrUnknownCategory
+	}
+	batch := &model.Batch{}
+	now := time.Now()
+	for _, line := range args.Lines {
+		q := model.ParseQuickAdd(line, now)
+		if q.Name == "" {
+			continue
+		}
+		batch.Tasks = append(batch.Tasks, q.Task(store, batch, args.Category))
+	}
+	if len(batch.Tasks) == 0 {
+		return nil, ErrEmptyName
+	}
+	return batch.Tasks, store.AddBatch(batch)
+}
+
+// listTasks returns the open tasks, or all of them, in the given category or
+// in every category.
+func listTasks(store *model.Store, args ListArgs) []*model.Task {
+	// Not nil: JSON-RPC takes a null result for a failed call.
+	tasks := []*model.Task{}
+	for _, t := range store.Tasks {
+		if (args.Category == "" || t.Category == args.Category) && (args.All || !t.Completed) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,254 @@
+// Package daemon lets one background process own a store and serve it to the
+// TUI and CLI over a Unix socket, using JSON-RPC. Clients make changes
+// through the daemon instead of rewriting the data file themselves, and can
+// wait for change notifications instead of polling.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"t7t/internal/model"
+)
+
+const (
+	// The socket lives in a directory only the owner can enter, so it's
+	// never reachable by others, not even before its own mode is set.
+	socketDir  = "run"
+	socketName = "t7t.sock"
+
+	// serviceName prefixes every RPC method.
+	serviceName = "T7T"
+
+	// pollInterval is how often the daemon checks the data file for changes
+	// made without it, such as by a sync tool.
+	pollInterval = time.Second
+
+	// waitTimeout bounds a Wait call so clients notice a dead daemon.
+	waitTimeout = 30 * time.Second
+)
+
+// ErrRunning is returned when another daemon already serves the directory.
+var ErrRunning = errors.New("daemon already running")
+
+// SocketPath returns the socket a daemon for the store in dir listens on.
+func SocketPath(dir string) string {
+	return filepath.Join(dir, socketDir, socketName)
+}
+
+// Running reports whether a daemon serves the store in dir.
+func Running(dir string) bool {
+	conn, err := net.Dial("unix", SocketPath(dir))
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// Daemon owns a store and counts its changes. The version increases with
+// every change, whether made through the daemon or found on disk.
+type Daemon struct {
+	// Warn, if set, is told about problems that don't fail a change, such
+	// as a save hook that couldn't run.
+	Warn func(error)
+
+	store   *model.Store
+	mu      sync.Mutex
+	version uint64
+	changed chan struct{}
+}
+
+func New(store *model.Store) *Daemon {
+	return &Daemon{store: store, version: 1, changed: make(chan struct{})}
+}
+
+// bump records a change and wakes up the waiting clients. The caller holds
+// d.mu.
+func (d *Daemon) bump() {
+	if err := d.store.HookError(); err != nil && d.Warn != nil {
+		d.Warn(err)
+	}
+	d.version++
+	close(d.changed)
+	d.changed = make(chan struct{})
+}
+
+// Serve listens on the store's socket until ctx is done. A socket left
+// behind by a daemon that died is replaced.
+func (d *Daemon) Serve(ctx context.Context) error {
+	path := SocketPath(d.store.Dir())
+	if Running(d.store.Dir()) {
+		return ErrRunning
+	}
+	os.Remove(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := os.Chmod(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	os.Chmod(path, 0600)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &Service{d}); err != nil {
+		listener.Close()
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	go d.poll(ctx)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// poll merges changes other processes wrote to the data file and notifies
+// the clients about them.
+func (d *Daemon) poll(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			if result, err := d.store.Reload(); err == nil && result.Changed {
+				d.bump()
+			}
+			d.mu.Unlock()
+		}
+	}
+}
+
+// Service holds the methods served over RPC.
+type Service struct {
+	d *Daemon
+}
+
+type AddArgs struct {
+	Name        string
+	Description string
+	Category    model.Category
+}
+
+// CaptureArgs holds lines of quick-add syntax, one task per line, see
+// model.ParseQuickAdd. Lines that don't give a category go to Category.
+type CaptureArgs struct {
+	Lines    []string
+	Category model.Category
+}
+
+// SaveArgs holds a client's copy of the data. Its settings only replace the
+// daemon's when the client changed them.
+type SaveArgs struct {
+	Data            *model.Store
+	SettingsChanged bool
+}
+
+type ListArgs struct {
+	Category model.Category
+	All      bool
+}
+
+type WaitArgs struct {
+	Since uint64
+}
+
+func (s *Service) Add(args AddArgs, reply *model.Task) error {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	task, err := addTask(s.d.store, args)
+	if err != nil {
+		return err
+	}
+	s.d.bump()
+	*reply = *task
+	return nil
+}
+
+func (s *Service) Capture(args CaptureArgs, reply *[]*model.Task) error {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	tasks, err := captureTasks(s.d.store, args)
+	if err != nil {
+		return err
+	}
+	s.d.bump()
+	*reply = tasks
+	return nil
+}
+
+// Save merges a client's copy of the data, with the changes it made, into
+// the daemon's store and writes it, so only the daemon writes the file.
+func (s *Service) Save(args SaveArgs, reply *struct{}) error {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	if _, err := s.d.store.Reload(); err != nil {
+		return err
+	}
+	if args.SettingsChanged {
+		s.d.store.Settings = args.Data.Settings
+	}
+	if _, err := s.d.store.Merge(args.Data, false); err != nil {
+		return err
+	}
+	s.d.bump()
+	return nil
+}
+
+func (s *Service) List(args ListArgs, reply *[]*model.Task) error {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	*reply = listTasks(s.d.store, args)
+	return nil
+}
+
+// Wait blocks until the store changes past the version since, or for a while
+// if it doesn't, and returns the current version. Since 0 returns at once.
+func (s *Service) Wait(args WaitArgs, reply *uint64) error {
+	s.d.mu.Lock()
+	version, changed := s.d.version, s.d.changed
+	s.d.mu.Unlock()
+
+	if version <= args.Since {
+		select {
+		case <-changed:
+		case <-time.After(waitTimeout):
+		}
+	}
+
+	s.d.mu.Lock()
+	*reply = s.d.version
+	s.d.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,311 @@
+// Package gitsync keeps a store's data directory in a git repository and
+// exchanges it with a remote, merging diverged histories task by task
+// instead of leaving textual conflicts in data.json.
+package gitsync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+const (
+	dataFile   = "data.json"
+	remoteName = "origin"
+
+	// Only the data file is versioned; backups, settings and other
+	// workspaces stay local.
+	gitignore = "*\n!.gitignore\n!" + dataFile + "\n"
+)
+
+// ErrNoGit is returned when the git executable can't be found.
+var ErrNoGit = errors.New("git not found")
+
+// ErrDifferentKey is returned when the other side's data is encrypted with
+// another key. Copies encrypted separately never share a key, even with the
+// same passphrase, so the other machines have to clone the encrypted
+// repository instead.
+var ErrDifferentKey = errors.New("the remote data is encrypted with a different key; clone the encrypted repository instead of encrypting each copy")
+
+// Result describes what a sync did.
+type Result struct {
+	Committed   string
+	FastForward bool
+	Merged      bool
+	Conflicts   []string
+	Pushed      bool
+}
+
+type repo struct {
+	dir string
+	env []string
+}
+
+func (r *repo) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(), r.env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// succeeds runs a git command that answers a yes/no question through its exit
+// status.
+func (r *repo) succeeds(args ...string) bool {
+	_, err := r.git(args...)
+	return err == nil
+}
+
+// Sync commits pending changes in the store's directory, then pulls from and
+// pushes to the configured remote if there is one. A non-empty remote URL
+// configures or replaces it first.
+func Sync(store *model.Store, remoteURL string) (Result, error) {
+	var result Result
+	r := &repo{dir: store.Dir()}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return result, ErrNoGit
+	}
+	if err := r.init(store); err != nil {
+		return result, err
+	}
+
+	if remoteURL != "" {
+		if r.succeeds("remote", "get-url", remoteName) {
+			_, err := r.git("remote", "set-url", remoteName, remoteURL)
+			if err != nil {
+				return result, err
+			}
+		} else if _, err := r.git("remote", "add", remoteName, remoteURL); err != nil {
+			return result, err
+		}
+	}
+
+	message, err := r.commitLocal(store)
+	if err != nil {
+		return result, err
+	}
+	result.Committed = message
+
+	if !r.succeeds("remote", "get-url", remoteName) {
+		return result, nil
+	}
+
+	branch, err := r.git("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return result, err
+	}
+	if _, err := r.git("fetch", "--quiet", remoteName); err != nil {
+		return result, err
+	}
+
+	remoteRef := "refs/remotes/" + remoteName + "/" + branch
+	if r.succeeds("rev-parse", "--verify", "--quiet", remoteRef) {
+		if err := r.pull(store, remoteRef, &result); err != nil {
+			return result, err
+		}
+	}
+
+	if _, err := r.git("push", "--quiet", "--set-upstream", remoteName, branch); err != nil {
+		return result, err
+	}
+	result.Pushed = true
+	return result, nil
+}
+
+// init turns the directory into a repository tracking only the data file and
+// makes sure commits work even without a configured git identity.
+func (r *repo) init(store *model.Store) error {
+	if _, err := os.Stat(filepath.Join(r.dir, ".git")); os.IsNotExist(err) {
+		if _, err := r.git("init", "--quiet"); err != nil {
+			return err
+		}
+	}
+
+	ignorePath := filepath.Join(r.dir, ".gitignore")
+	if _, err := os.Stat(ignorePath); os.IsNotExist(err) {
+		if err := os.WriteFile(ignorePath, []byte(gitignore), 0644); err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(r.dir, dataFile)); os.IsNotExist(err) {
+		if err := store.Save(); err != nil {
+			return err
+		}
+	}
+
+	if !r.succeeds("config", "user.email") {
+		r.env = []string{
+			"GIT_AUTHOR_NAME=t7t", "GIT_AUTHOR_EMAIL=t7t@localhost",
+			"GIT_COMMITTER_NAME=t7t", "GIT_COMMITTER_EMAIL=t7t@localhost",
+		}
+	}
+	return nil
+}
+
+// commitLocal commits the working copy if it changed, describing the change
+// set in the message. It returns the message, or "" if there was nothing to
+// commit.
+func (r *repo) commitLocal(store *model.Store) (string, error) {
+	if _, err := r.git("add", "--all"); err != nil {
+		return "", err
+	}
+	if r.succeeds("diff", "--cached", "--quiet") && r.succeeds("rev-parse", "--verify", "--quiet", "HEAD") {
+		return "", nil
+	}
+
+	before, err := r.version(store, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	current, err := os.ReadFile(filepath.Join(r.dir, dataFile))
+	if err != nil {
+		return "", err
+	}
+	after, err := store.ParseVersion(current)
+	if err != nil {
+		return "", err
+	}
+
+	message := commitMessage(model.DescribeChanges(before, after), store.Encrypted())
+	if _, err := r.git("commit", "--quiet", "--message", message); err != nil {
+		return "", err
+	}
+	return strings.SplitN(message, "\n", 2)[0], nil
+}
+
+// version reads the data file as of the given revision, or nil if it doesn't
+// exist there.
+func (r *repo) version(store *model.Store, rev string) (*model.Store, error) {
+	if !r.succeeds("cat-file", "-e", rev+":"+dataFile) {
+		return nil, nil
+	}
+	data, err := r.git("show", rev+":"+dataFile)
+	if err != nil {
+		return nil, err
+	}
+	version, err := store.ParseVersion([]byte(data))
+	if errors.Is(err, model.ErrDifferentKey) {
+		return nil, ErrDifferentKey
+	}
+	return version, err
+}
+
+// pull brings in the remote branch: a fast-forward when the local branch has
+// nothing new, otherwise a merge commit whose data file is the task-level
+// three-way merge of both sides.
+func (r *repo) pull(store *model.Store, remoteRef string, result *Result) error {
+	if r.succeeds("merge-base", "--is-ancestor", remoteRef, "HEAD") {
+		return nil
+	}
+
+	if r.succeeds("merge-base", "--is-ancestor", "HEAD", remoteRef) {
+		if _, err := r.git("merge", "--quiet", "--ff-only", remoteRef); err != nil {
+			return err
+		}
+		result.FastForward = true
+		return store.Load()
+	}
+
+	var base *model.Store
+	mergeArgs := []string{"merge", "--quiet", "--no-commit", "--strategy", "ours"}
+	if mergeBase, err := r.git("merge-base", "HEAD", remoteRef); err == nil {
+		if base, err = r.version(store, mergeBase); err != nil {
+			return err
+		}
+	} else {
+		mergeArgs = append(mergeArgs, "--allow-unrelated-histories")
+	}
+	theirs, err := r.version(store, remoteRef)
+	if err != nil {
+		return err
+	}
+	if theirs == nil {
+		theirs = &model.Store{}
+	}
+
+	if _, err := r.git(append(mergeArgs, remoteRef)...); err != nil {
+		return err
+	}
+	conflicts, err := store.MergeVersion(base, theirs)
+	if err != nil {
+		r.git("merge", "--abort")
+		return err
+	}
+	if _, err := r.git("add", dataFile); err != nil {
+		return err
+	}
+
+	m := i18n.Get()
+	message := m.SyncMergeMessage
+	if len(conflicts) > 0 && !store.Encrypted() {
+		message += "\n\n" + m.SyncMergeConflicts + "\n- " + strings.Join(conflicts, "\n- ")
+	}
+	if _, err := r.git("commit", "--quiet", "--message", message); err != nil {
+		return err
+	}
+
+	result.Merged = true
+	result.Conflicts = conflicts
+	return nil
+}
+
+// changeMarkers prefix the task names in a commit message body: added,
+// completed, updated and deleted.
+var changeMarkers = []string{"+", "x", "~", "-"}
+
+// commitMessage summarizes a change set in the subject line and lists the
+// affected tasks in the body. Task names are left out for encrypted stores,
+// since commit messages are stored in plain text.
+func commitMessage(c model.ChangeSummary, encrypted bool) string {
+	m := i18n.Get()
+
+	var parts []string
+	sections := []struct {
+		format string
+		names  []string
+	}{
+		{m.SyncAdded, c.Added},
+		{m.SyncCompleted, c.Completed},
+		{m.SyncUpdated, c.Updated},
+		{m.SyncDeleted, c.Deleted},
+	}
+	for _, s := range sections {
+		if len(s.names) > 0 {
+			parts = append(parts, fmt.Sprintf(s.format, len(s.names)))
+		}
+	}
+	if len(parts) == 0 {
+		return m.SyncNoTaskChanges
+	}
+
+	message := strings.Join(parts, ", ")
+	if encrypted {
+		return message
+	}
+
+	var body []string
+	for i, s := range sections {
+		for _, name := range s.names {
+			body = append(body, changeMarkers[i]+" "+name)
+		}
+	}
+	return message + "\n\n" + strings.Join(body, "\n")
+}
@@ -0,0 +1,177 @@
+package gitsync
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"t7t/internal/model"
+)
+
+// newRemote creates a bare repository to sync with and keeps git away from
+// the user's configuration.
+func newRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	remote := filepath.Join(t.TempDir(), "tasks.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	return remote
+}
+
+// clone checks the remote out into a new data directory, the way a second
+// machine starts syncing.
+func clone(t *testing.T, remote string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "t7t")
+	if out, err := exec.Command("git", "clone", "--quiet", remote, dir).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v: %s", err, out)
+	}
+	return dir
+}
+
+func openStore(t *testing.T, dir string) *model.Store {
+	t.Helper()
+	store, err := model.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func runSync(t *testing.T, store *model.Store, remote string) Result {
+	t.Helper()
+	result, err := Sync(store, remote)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	return result
+}
+
+func addTask(t *testing.T, store *model.Store, name string) *model.Task {
+	t.Helper()
+	task := model.NewTask(name, "", model.CategoryToday)
+	if err := store.AddTask(task); err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func rename(t *testing.T, store *model.Store, id, name string, at time.Time) {
+	t.Helper()
+	task := store.GetTask(id)
+	task.Update(name, task.Description)
+	task.UpdatedAt = at
+	if err := store.UpdateTask(task); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncMergesDivergedClones(t *testing.T) {
+	remote := newRemote(t)
+
+	laptop := openStore(t, t.TempDir())
+	shared := addTask(t, laptop, "Write report")
+	other := addTask(t, laptop, "Call mom")
+	if result := runSync(t, laptop, remote); !result.Pushed || result.Committed == "" {
+		t.Fatalf("first sync %+v, want a commit pushed", result)
+	}
+
+	desktop := openStore(t, clone(t, remote))
+	if desktop.GetTask(shared.ID) == nil {
+		t.Fatal("clone is missing the laptop's tasks")
+	}
+
+	// Both machines rename the same task, the desktop later. Each side also
+	// makes a change the other doesn't.
+	now := time.Now()
+	rename(t, laptop, shared.ID, "Write laptop report", now)
+	rename(t, laptop, other.ID, "Call dad", now)
+	rename(t, desktop, shared.ID, "Write desktop report", now.Add(time.Minute))
+	added := addTask(t, desktop, "Buy milk")
+
+	runSync(t, laptop, remote)
+	result := runSync(t, desktop, remote)
+	if !result.Merged || !result.Pushed {
+		t.Fatalf("desktop sync %+v, want a merge pushed", result)
+	}
+	if !slices.Equal(result.Conflicts, []string{"Write desktop report"}) {
+		t.Errorf("conflicts %v, want the task renamed on both sides", result.Conflicts)
+	}
+
+	// The laptop catches up without merging again.
+	if result := runSync(t, laptop, remote); !result.FastForward {
+		t.Errorf("laptop sync %+v, want a fast-forward", result)
+	}
+	for _, store := range []*model.Store{laptop, desktop} {
+		if got := store.GetTask(shared.ID).Name; got != "Write desktop report" {
+			t.Errorf("shared task %q, want the newer desktop name", got)
+		}
+		if got := store.GetTask(other.ID).Name; got != "Call dad" {
+			t.Errorf("laptop-only change %q, want it kept", got)
+		}
+		if store.GetTask(added.ID) == nil {
+			t.Error("desktop-only task missing")
+		}
+	}
+}
+
+func TestSyncEncryptedClone(t *testing.T) {
+	remote := newRemote(t)
+
+	laptop := openStore(t, t.TempDir())
+	task := addTask(t, laptop, "Write report")
+	if err := laptop.Encrypt("secret"); err != nil {
+		t.Fatal(err)
+	}
+	runSync(t, laptop, remote)
+
+	desktop := openStore(t, clone(t, remote))
+	if err := desktop.Unlock("secret"); err != nil {
+		t.Fatal(err)
+	}
+	rename(t, desktop, task.ID, "Write final report", time.Now().Add(time.Minute))
+	addTask(t, laptop, "Call mom")
+
+	runSync(t, laptop, remote)
+	if result := runSync(t, desktop, remote); !result.Merged {
+		t.Fatalf("desktop sync %+v, want a merge", result)
+	}
+	runSync(t, laptop, remote)
+	if got := laptop.GetTask(task.ID).Name; got != "Write final report" {
+		t.Errorf("laptop has %q, want the desktop's change", got)
+	}
+}
+
+func TestSyncRefusesSeparatelyEncryptedCopies(t *testing.T) {
+	remote := newRemote(t)
+
+	laptop := openStore(t, t.TempDir())
+	addTask(t, laptop, "Write report")
+	if err := laptop.Encrypt("secret"); err != nil {
+		t.Fatal(err)
+	}
+	runSync(t, laptop, remote)
+
+	// Same passphrase, but encrypted on its own: the salt differs.
+	desktop := openStore(t, t.TempDir())
+	addTask(t, desktop, "Call mom")
+	if err := desktop.Encrypt("secret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Sync(desktop, remote); !errors.Is(err, ErrDifferentKey) {
+		t.Fatalf("Sync: %v, want ErrDifferentKey", err)
+	}
+	if n := len(desktop.Tasks); n != 1 {
+		t.Errorf("desktop has %d tasks after the refused sync, want 1", n)
+	}
+}
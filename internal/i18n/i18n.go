@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"t7t/internal/config"
 )
 
 type Language string
@@ -33,6 +35,7 @@ type Messages struct {
 	StatusTaskReopened     string `json:"status_task_reopened"`
 	StatusTaskMoved        string `json:"status_task_moved"`
 	StatusTaskCreated      string `json:"status_task_created"`
+	StatusTaskNameMissing  string `json:"status_task_name_missing"`
 	StatusTaskUpdated      string `json:"status_task_updated"`
 	StatusProjectsAssoc    string `json:"status_projects_assoc"`
 	StatusProjectCompleted string `json:"status_project_completed"`
@@ -89,6 +92,7 @@ type Messages struct {
 	HintFormFields     string `json:"hint_form_fields"`
 	HintProjectForm    string `json:"hint_project_form"`
 	HintAssocProjects  string `json:"hint_assoc_projects"`
+	HintDependencies   string `json:"hint_dependencies"`
 	HintCloseHelp      string `json:"hint_close_help"`
 	HintNoProjectAvail string `json:"hint_no_project_avail"`
 
@@ -179,11 +183,269 @@ type Messages struct {
 	ProjectCompleted    string `json:"project_completed"`
 
 	// Language selection
-	LanguageSelect   string `json:"language_select"`
-	LanguagePortBR   string `json:"language_port_br"`
-	LanguageEnglish  string `json:"language_english"`
-	LanguageHint     string `json:"language_hint"`
-	LanguageChanged  string `json:"language_changed"`
+	LanguageSelect  string `json:"language_select"`
+	LanguagePortBR  string `json:"language_port_br"`
+	LanguageEnglish string `json:"language_english"`
+	LanguageHint    string `json:"language_hint"`
+	LanguageChanged string `json:"language_changed"`
+
+	// Review mode
+	ReviewTitleList        string `json:"review_title_list"`
+	ReviewTitleAll         string `json:"review_title_all"`
+	ReviewProgress         string `json:"review_progress"`
+	ReviewLabelList        string `json:"review_label_list"`
+	ReviewHint             string `json:"review_hint"`
+	ReviewEmpty            string `json:"review_empty"`
+	ReviewSummaryTitle     string `json:"review_summary_title"`
+	ReviewSummaryReviewed  string `json:"review_summary_reviewed"`
+	ReviewSummaryKept      string `json:"review_summary_kept"`
+	ReviewSummaryMoved     string `json:"review_summary_moved"`
+	ReviewSummaryCompleted string `json:"review_summary_completed"`
+	ReviewSummaryDeferred  string `json:"review_summary_deferred"`
+	ReviewSummaryDeleted   string `json:"review_summary_deleted"`
+	ReviewSummaryChanges   string `json:"review_summary_changes"`
+	ReviewSummaryNoChanges string `json:"review_summary_no_changes"`
+	ReviewActionMoved      string `json:"review_action_moved"`
+	ReviewActionCompleted  string `json:"review_action_completed"`
+	ReviewActionDeferred   string `json:"review_action_deferred"`
+	ReviewActionDeleted    string `json:"review_action_deleted"`
+	ReviewSummaryHint      string `json:"review_summary_hint"`
+	ReviewFinished         string `json:"review_finished"`
+	KeyReview              string `json:"key_review"`
+	KeyReviewAll           string `json:"key_review_all"`
+	HelpReviewSection      string `json:"help_review_section"`
+	HelpReviewList         string `json:"help_review_list"`
+	HelpReviewAll          string `json:"help_review_all"`
+
+	// Statistics
+	StatsTitle       string `json:"stats_title"`
+	StatsBackToTasks string `json:"stats_back_to_tasks"`
+	StatsThroughput  string `json:"stats_throughput"`
+	StatsToday       string `json:"stats_today"`
+	StatsThisWeek    string `json:"stats_this_week"`
+	StatsLastWeek    string `json:"stats_last_week"`
+	StatsWeekly      string `json:"stats_weekly"`
+	StatsOpenAge     string `json:"stats_open_age"`
+	StatsOpenAgeLine string `json:"stats_open_age_line"`
+	StatsProjects    string `json:"stats_projects"`
+	StatsNoProjects  string `json:"stats_no_projects"`
+	StatsDays        string `json:"stats_days"`
+	StatsHours       string `json:"stats_hours"`
+	StatsDateFormat  string `json:"stats_date_format"`
+	KeyStats         string `json:"key_stats"`
+	HelpNavStats     string `json:"help_nav_stats"`
+
+	// Time tracking
+	StatusTimerStarted string `json:"status_timer_started"`
+	StatusTimerStopped string `json:"status_timer_stopped"`
+	LabelTimeTracked   string `json:"label_time_tracked"`
+	KeyTimer           string `json:"key_timer"`
+	HelpTaskTimer      string `json:"help_task_timer"`
+
+	// Command line
+	CLIUsage           string `json:"cli_usage"`
+	CLIUnknownCommand  string `json:"cli_unknown_command"`
+	CLIMissingArgument string `json:"cli_missing_argument"`
+	CLIExtraArgument   string `json:"cli_extra_argument"`
+	CLITaskNotFound    string `json:"cli_task_not_found"`
+	CLITaskAmbiguous   string `json:"cli_task_ambiguous"`
+	CLITimerStarted    string `json:"cli_timer_started"`
+	CLITimerStopped    string `json:"cli_timer_stopped"`
+	CLITimerNone       string `json:"cli_timer_none"`
+	CLITimerRunning    string `json:"cli_timer_running"`
+	CLIReportTitle     string `json:"cli_report_title"`
+	CLIReportByTask    string `json:"cli_report_by_task"`
+	CLIReportByProject string `json:"cli_report_by_project"`
+	CLIReportByDay     string `json:"cli_report_by_day"`
+	CLIReportNoProject string `json:"cli_report_no_project"`
+	ErrorCommand       string `json:"error_command"`
+
+	// Focus mode
+	FocusTitle           string `json:"focus_title"`
+	FocusPhaseWork       string `json:"focus_phase_work"`
+	FocusPhaseShortBreak string `json:"focus_phase_short_break"`
+	FocusPhaseLongBreak  string `json:"focus_phase_long_break"`
+	FocusPaused          string `json:"focus_paused"`
+	FocusPomodoros       string `json:"focus_pomodoros"`
+	FocusSession         string `json:"focus_session"`
+	FocusSettings        string `json:"focus_settings"`
+	FocusCelebrate       string `json:"focus_celebrate"`
+	FocusYes             string `json:"focus_yes"`
+	FocusNo              string `json:"focus_no"`
+	FocusHint            string `json:"focus_hint"`
+	StatusPomodoroDone   string `json:"status_pomodoro_done"`
+	StatusBreakDone      string `json:"status_break_done"`
+	LabelPomodoros       string `json:"label_pomodoros"`
+	KeyFocus             string `json:"key_focus"`
+	HelpTaskFocus        string `json:"help_task_focus"`
+
+	// Dependencies
+	ModalDependencies     string `json:"modal_dependencies"`
+	HintNoOtherTasks      string `json:"hint_no_other_tasks"`
+	LabelBlocked          string `json:"label_blocked"`
+	LabelBlockedBy        string `json:"label_blocked_by"`
+	LabelBlocks           string `json:"label_blocks"`
+	StatusDependenciesSet string `json:"status_dependencies_set"`
+	StatusDependencyCycle string `json:"status_dependency_cycle"`
+	StatusUnblocked       string `json:"status_unblocked"`
+	KeyDependencies       string `json:"key_dependencies"`
+	HelpTaskDeps          string `json:"help_task_deps"`
+
+	// Persistence errors
+	StatusSaveFailed    string `json:"status_save_failed"`
+	StatusSaveRecovered string `json:"status_save_recovered"`
+	BannerUnsaved       string `json:"banner_unsaved"`
+	ModalUnsavedQuit    string `json:"modal_unsaved_quit"`
+	ConfirmUnsavedQuit  string `json:"confirm_unsaved_quit"`
+	UnsavedRetry        string `json:"unsaved_retry"`
+	UnsavedDiscard      string `json:"unsaved_discard"`
+
+	// Live reload
+	StatusReloaded        string `json:"status_reloaded"`
+	StatusReloadConflicts string `json:"status_reload_conflicts"`
+	StatusReloadFailed    string `json:"status_reload_failed"`
+
+	// Workspaces
+	KeyWorkspace            string `json:"key_workspace"`
+	HelpGeneralWorkspace    string `json:"help_general_workspace"`
+	ModalWorkspace          string `json:"modal_workspace"`
+	ModalNewWorkspace       string `json:"modal_new_workspace"`
+	FormWorkspaceName       string `json:"form_workspace_name"`
+	WorkspaceHint           string `json:"workspace_hint"`
+	StatusWorkspaceSwitched string `json:"status_workspace_switched"`
+	StatusWorkspaceUnsaved  string `json:"status_workspace_unsaved"`
+	StatusWorkspaceInvalid  string `json:"status_workspace_invalid"`
+	StatusWorkspaceFailed   string `json:"status_workspace_failed"`
+	ErrorInvalidWorkspace   string `json:"error_invalid_workspace"`
+
+	// Backups
+	BackupCounts         string `json:"backup_counts"`
+	CLIBackupNone        string `json:"cli_backup_none"`
+	CLIBackupNotFound    string `json:"cli_backup_not_found"`
+	CLIBackupRestored    string `json:"cli_backup_restored"`
+	KeyBackups           string `json:"key_backups"`
+	HelpGeneralBackups   string `json:"help_general_backups"`
+	ModalBackups         string `json:"modal_backups"`
+	BackupsEmpty         string `json:"backups_empty"`
+	BackupsHint          string `json:"backups_hint"`
+	BackupConfirm        string `json:"backup_confirm"`
+	BackupCurrent        string `json:"backup_current"`
+	BackupSelected       string `json:"backup_selected"`
+	StatusBackupRestored string `json:"status_backup_restored"`
+	StatusBackupFailed   string `json:"status_backup_failed"`
+
+	// Encryption
+	CLIPassphrasePrompt   string `json:"cli_passphrase_prompt"`
+	CLINewPassphrase      string `json:"cli_new_passphrase"`
+	CLIConfirmPassphrase  string `json:"cli_confirm_passphrase"`
+	CLIWrongPassphrase    string `json:"cli_wrong_passphrase"`
+	CLIEmptyPassphrase    string `json:"cli_empty_passphrase"`
+	CLIPassphraseMismatch string `json:"cli_passphrase_mismatch"`
+	CLIAlreadyEncrypted   string `json:"cli_already_encrypted"`
+	CLINotEncrypted       string `json:"cli_not_encrypted"`
+	CLIEncrypted          string `json:"cli_encrypted"`
+	CLIDecrypted          string `json:"cli_decrypted"`
+	StatusWorkspaceLocked string `json:"status_workspace_locked"`
+
+	// Git sync
+	SyncAdded          string `json:"sync_added"`
+	SyncCompleted      string `json:"sync_completed"`
+	SyncUpdated        string `json:"sync_updated"`
+	SyncDeleted        string `json:"sync_deleted"`
+	SyncNoTaskChanges  string `json:"sync_no_task_changes"`
+	SyncMergeMessage   string `json:"sync_merge_message"`
+	SyncMergeConflicts string `json:"sync_merge_conflicts"`
+	CLISyncCommitted   string `json:"cli_sync_committed"`
+	CLISyncPulled      string `json:"cli_sync_pulled"`
+	CLISyncMerged      string `json:"cli_sync_merged"`
+	CLISyncConflicts   string `json:"cli_sync_conflicts"`
+	CLISyncPushed      string `json:"cli_sync_pushed"`
+	CLISyncNoRemote    string `json:"cli_sync_no_remote"`
+
+	// Merge
+	MergeTask           string `json:"merge_task"`
+	MergeProject        string `json:"merge_project"`
+	CLIMergeAdded       string `json:"cli_merge_added"`
+	CLIMergeKept        string `json:"cli_merge_kept"`
+	CLIMergeRemoved     string `json:"cli_merge_removed"`
+	CLIMergeSurvived    string `json:"cli_merge_survived"`
+	CLIMergeStayDeleted string `json:"cli_merge_stay_deleted"`
+	CLIMergeRestored    string `json:"cli_merge_restored"`
+	CLIMergeTookField   string `json:"cli_merge_took_field"`
+	CLIMergeKeptField   string `json:"cli_merge_kept_field"`
+	CLIMergeSummary     string `json:"cli_merge_summary"`
+	CLIMergeDryRun      string `json:"cli_merge_dry_run"`
+
+	// API server
+	CLIServeListening string `json:"cli_serve_listening"`
+	CLIServeToken     string `json:"cli_serve_token"`
+	CLIServeTokenEnv  string `json:"cli_serve_token_env"`
+
+	// Daemon
+	CLIDaemonListening string `json:"cli_daemon_listening"`
+	CLIDaemonRunning   string `json:"cli_daemon_running"`
+	CLITaskAdded       string `json:"cli_task_added"`
+	CLIListEmpty       string `json:"cli_list_empty"`
+	CLIUnknownCategory string `json:"cli_unknown_category"`
+
+	// CalDAV
+	LabelDue                string `json:"label_due"`
+	CLICalDAVNoURL          string `json:"cli_caldav_no_url"`
+	CLICalDAVPasswordPrompt string `json:"cli_caldav_password_prompt"`
+	CLICalDAVResult         string `json:"cli_caldav_result"`
+	CLICalDAVConflicts      string `json:"cli_caldav_conflicts"`
+
+	// Import and export
+	LabelPriority    string `json:"label_priority"`
+	CLIImported      string `json:"cli_imported"`
+	CLIUnknownFormat string `json:"cli_unknown_format"`
+
+	// Markdown export
+	KeyExport             string `json:"key_export"`
+	HelpGeneralExport     string `json:"help_general_export"`
+	StatusExported        string `json:"status_exported"`
+	StatusExportFailed    string `json:"status_export_failed"`
+	StatusExportEncrypted string `json:"status_export_encrypted"`
+	CLIUnknownGrouping    string `json:"cli_unknown_grouping"`
+
+	// Import preview
+	CLIImportProject      string `json:"cli_import_project"`
+	CLIImportTaskProjects string `json:"cli_import_task_projects"`
+	CLIImportDryRun       string `json:"cli_import_dry_run"`
+
+	// CSV
+	CLIImportLineError   string `json:"cli_import_line_error"`
+	CLIImportInvalidRows string `json:"cli_import_invalid_rows"`
+	CLIBadPair           string `json:"cli_bad_pair"`
+	CLIBadSeparator      string `json:"cli_bad_separator"`
+
+	// Calendar export
+	CLIFeedEnabled  string `json:"cli_feed_enabled"`
+	CLIFeedDisabled string `json:"cli_feed_disabled"`
+
+	// Import updates
+	CLIImportChanges       string `json:"cli_import_changes"`
+	CLIImportUpdated       string `json:"cli_import_updated"`
+	CLIImportDryRunUpdates string `json:"cli_import_dry_run_updates"`
+
+	// HTML report
+	ReportTitle             string `json:"report_title"`
+	ReportGenerated         string `json:"report_generated"`
+	ReportDateFormat        string `json:"report_date_format"`
+	ReportDateTimeFormat    string `json:"report_date_time_format"`
+	ReportDue               string `json:"report_due"`
+	ReportNoOpenTasks       string `json:"report_no_open_tasks"`
+	ReportRecentlyCompleted string `json:"report_recently_completed"`
+	ReportNoRecent          string `json:"report_no_recent"`
+
+	// Quick add
+	FormQuickAddHint       string `json:"form_quick_add_hint"`
+	FormQuickAddNewProject string `json:"form_quick_add_new_project"`
+
+	// Save hook warnings
+	CLIFeedEncrypted     string `json:"cli_feed_encrypted"`
+	WarningSaveHook      string `json:"warning_save_hook"`
+	StatusSaveHookFailed string `json:"status_save_hook_failed"`
 
 	// Error messages
 	ErrorInitStorage string `json:"error_init_storage"`
@@ -221,6 +483,7 @@ var ptBR = &Messages{
 	StatusTaskReopened:     "Tarefa reaberta",
 	StatusTaskMoved:        "Tarefa movida para %s",
 	StatusTaskCreated:      "Tarefa criada",
+	StatusTaskNameMissing:  "Digite um nome alem de !lista, +projeto e due:",
 	StatusTaskUpdated:      "Tarefa atualizada",
 	StatusProjectsAssoc:    "Projetos associados",
 	StatusProjectCompleted: "Projeto concluido",
@@ -277,6 +540,7 @@ var ptBR = &Messages{
 	HintFormFields:     "Tab: alternar campos | Ctrl+S: salvar | Esc: cancelar",
 	HintProjectForm:    "Enter: confirmar | Esc: cancelar",
 	HintAssocProjects:  "Space: selecionar | Enter: confirmar | Esc: cancelar",
+	HintDependencies:   "Space: bloqueia/libera | Enter: confirmar | Esc: cancelar",
 	HintCloseHelp:      "Pressione qualquer tecla para fechar",
 	HintNoProjectAvail: "Nenhum projeto disponivel.\nCrie um projeto primeiro (P).",
 
@@ -371,6 +635,264 @@ var ptBR = &Messages{
 	LanguageHint:    "j/k: navegar | Enter: selecionar | Esc: cancelar",
 	LanguageChanged: "Idioma alterado",
 
+	// Review mode
+	ReviewTitleList:        "Revisao - %s",
+	ReviewTitleAll:         "Revisao - Todas as listas",
+	ReviewProgress:         "Tarefa %d de %d",
+	ReviewLabelList:        "Lista: ",
+	ReviewHint:             "Enter/k: manter | 1-4: mover | x: concluir | f: adiar | d: deletar | Esc: encerrar",
+	ReviewEmpty:            "Nenhuma tarefa aberta para revisar",
+	ReviewSummaryTitle:     "Resumo da Revisao",
+	ReviewSummaryReviewed:  "Revisadas: %d de %d",
+	ReviewSummaryKept:      "Mantidas: %d",
+	ReviewSummaryMoved:     "Movidas: %d",
+	ReviewSummaryCompleted: "Concluidas: %d",
+	ReviewSummaryDeferred:  "Adiadas: %d",
+	ReviewSummaryDeleted:   "Deletadas: %d",
+	ReviewSummaryChanges:   "Alteracoes:",
+	ReviewSummaryNoChanges: "(nenhuma alteracao)",
+	ReviewActionMoved:      "movida para %s",
+	ReviewActionCompleted:  "concluida",
+	ReviewActionDeferred:   "adiada para %s",
+	ReviewActionDeleted:    "deletada",
+	ReviewSummaryHint:      "Pressione qualquer tecla para voltar",
+	ReviewFinished:         "Revisao concluida",
+	KeyReview:              "revisar lista",
+	KeyReviewAll:           "revisar tudo",
+	HelpReviewSection:      "Revisao",
+	HelpReviewList:         "Revisar lista atual (diaria)",
+	HelpReviewAll:          "Revisar todas as listas (semanal)",
+
+	// Statistics
+	StatsTitle:       "Estatisticas",
+	StatsBackToTasks: "[S] Voltar para Tarefas",
+	StatsThroughput:  "Concluidas por dia (ultimos %d dias)",
+	StatsToday:       "Hoje: %d",
+	StatsThisWeek:    "Esta semana: %d",
+	StatsLastWeek:    "Semana passada: %d",
+	StatsWeekly:      "Concluidas por semana",
+	StatsOpenAge:     "Tarefas abertas por lista",
+	StatsOpenAgeLine: "%d abertas, idade media %s",
+	StatsProjects:    "Progresso dos projetos",
+	StatsNoProjects:  "(nenhum projeto)",
+	StatsDays:        "%dd",
+	StatsHours:       "%dh",
+	StatsDateFormat:  "02/01",
+	KeyStats:         "estatisticas",
+	HelpNavStats:     "Tela de estatisticas",
+
+	// Time tracking
+	StatusTimerStarted: "Timer iniciado",
+	StatusTimerStopped: "Timer parado (%s)",
+	LabelTimeTracked:   "Tempo registrado: ",
+	KeyTimer:           "iniciar/parar timer",
+	HelpTaskTimer:      "Iniciar/parar timer da tarefa",
+
+	// Command line
+	CLIUsage:           "Uso:\n  t7t                          abre a interface\n  t7t add [-category C] <nome> adiciona uma tarefa\n  t7t add - | t7t capture       adiciona uma tarefa por linha da entrada padrao\n                               (!week, +projeto, @contexto, due:sex)\n  t7t list [-category C] [-all] lista as tarefas abertas\n  t7t daemon                   serve os dados para a interface e a linha de comando\n  t7t timer start <tarefa>     inicia o timer da tarefa (ID ou nome)\n  t7t timer stop               para o timer em andamento\n  t7t timer status             mostra o timer em andamento\n  t7t timer report [-days N]   totais por tarefa, projeto e dia\n  t7t backup list              lista os backups automaticos\n  t7t backup restore <backup>  restaura um backup\n  t7t encrypt                  criptografa os dados com uma senha\n  t7t decrypt                  volta a salvar os dados sem criptografia\n  t7t sync [-remote URL]       sincroniza os dados com um repositorio git\n  t7t caldav [-url URL] [-user USUARIO]\n                               sincroniza as tarefas com um servidor CalDAV\n  t7t merge [-dry-run] <arq>   mescla outra copia do arquivo de dados\n  t7t serve [-addr ENDERECO]   serve uma API HTTP/JSON local\n  t7t import <formato> [-dry-run] <arq>\n                               importa tarefas (todotxt, markdown, csv,\n                               taskwarrior, org)\n  t7t export <formato> [-o ARQ]\n                               exporta as tarefas (todotxt, markdown, csv,\n                               ics, taskwarrior, org)\n  t7t report html [-o ARQ]     gera uma pagina HTML com o status das tarefas\n\nOpcoes (antes do comando):\n  --data-dir DIR               usa DIR como diretorio de dados\n  -w, --workspace NOME         usa o workspace NOME\n",
+	CLIUnknownCommand:  "comando desconhecido: %s",
+	CLIMissingArgument: "argumento ausente: %s",
+	CLIExtraArgument:   "argumento inesperado: %s",
+	CLITaskNotFound:    "nenhuma tarefa corresponde a %q",
+	CLITaskAmbiguous:   "%d tarefas correspondem a %q, use o ID",
+	CLITimerStarted:    "Timer iniciado: %s\n",
+	CLITimerStopped:    "Timer parado: %s (%s)\n",
+	CLITimerNone:       "Nenhum timer em andamento\n",
+	CLITimerRunning:    "Timer em andamento: %s (%s)\n",
+	CLIReportTitle:     "Tempo registrado nos ultimos %d dias: %s\n",
+	CLIReportByTask:    "Por tarefa:",
+	CLIReportByProject: "Por projeto:",
+	CLIReportByDay:     "Por dia:",
+	CLIReportNoProject: "(sem projeto)",
+	ErrorCommand:       "Erro: %v\n",
+
+	// Focus mode
+	FocusTitle:           "Foco",
+	FocusPhaseWork:       "Trabalho",
+	FocusPhaseShortBreak: "Pausa curta",
+	FocusPhaseLongBreak:  "Pausa longa",
+	FocusPaused:          "(pausado)",
+	FocusPomodoros:       "Pomodoros da tarefa: %d",
+	FocusSession:         "Nesta sessao: %d",
+	FocusSettings:        "Trabalho %dm | Pausa curta %dm | Pausa longa %dm (a cada %d)",
+	FocusCelebrate:       "Comemorar ao fim do pomodoro: %s",
+	FocusYes:             "sim",
+	FocusNo:              "nao",
+	FocusHint:            "Space: iniciar/pausar | s: pular | r: reiniciar | +/-: duracao | c: comemorar | Esc: sair",
+	StatusPomodoroDone:   "Pomodoro concluido! Hora da pausa",
+	StatusBreakDone:      "Pausa encerrada, de volta ao trabalho",
+	LabelPomodoros:       "Pomodoros: ",
+	KeyFocus:             "modo foco",
+	HelpTaskFocus:        "Modo foco (Pomodoro)",
+
+	// Dependencies
+	ModalDependencies:     "Tarefas Bloqueadoras",
+	HintNoOtherTasks:      "Nenhuma outra tarefa disponivel.",
+	LabelBlocked:          "Bloqueada",
+	LabelBlockedBy:        "Bloqueada por:",
+	LabelBlocks:           "Bloqueia:",
+	StatusDependenciesSet: "Dependencias atualizadas",
+	StatusDependencyCycle: "Dependencia circular detectada, alteracao descartada",
+	StatusUnblocked:       "Desbloqueada(s): %s",
+	KeyDependencies:       "bloqueadoras",
+	HelpTaskDeps:          "Definir tarefas bloqueadoras",
+
+	// Persistence errors
+	StatusSaveFailed:    "Erro ao salvar: %v",
+	StatusSaveRecovered: "Alteracoes salvas",
+	BannerUnsaved:       "Alteracoes nao salvas: %v - nova tentativa a cada %ds",
+	ModalUnsavedQuit:    "Alteracoes Nao Salvas",
+	ConfirmUnsavedQuit:  "Suas alteracoes ainda nao foram salvas:",
+	UnsavedRetry:        "tentar salvar e sair",
+	UnsavedDiscard:      "sair sem salvar",
+
+	// Live reload
+	StatusReloaded:        "Alteracoes externas carregadas",
+	StatusReloadConflicts: "Editado aqui e em outro lugar, mantida a versao mais recente: %s",
+	StatusReloadFailed:    "Erro ao recarregar os dados: %v",
+
+	// Workspaces
+	KeyWorkspace:            "workspaces",
+	HelpGeneralWorkspace:    "Trocar de workspace",
+	ModalWorkspace:          "Workspaces",
+	ModalNewWorkspace:       "Novo Workspace",
+	FormWorkspaceName:       "Nome:",
+	WorkspaceHint:           "j/k: navegar | Enter: abrir | a: novo | Esc: cancelar",
+	StatusWorkspaceSwitched: "Workspace: %s",
+	StatusWorkspaceUnsaved:  "Existem alteracoes nao salvas, tente novamente apos salvar",
+	StatusWorkspaceInvalid:  "Nome de workspace invalido: %q",
+	StatusWorkspaceFailed:   "Erro ao abrir workspace: %v",
+	ErrorInvalidWorkspace:   "Nome de workspace invalido: %q\n",
+
+	// Backups
+	BackupCounts:         "%d tarefas (%d abertas), %d projetos",
+	CLIBackupNone:        "Nenhum backup ainda\n",
+	CLIBackupNotFound:    "backup %q nao encontrado, veja t7t backup list",
+	CLIBackupRestored:    "Restaurado %s: %s\n",
+	KeyBackups:           "backups",
+	HelpGeneralBackups:   "Restaurar um backup",
+	ModalBackups:         "Restaurar Backup",
+	BackupsEmpty:         "Nenhum backup ainda. Eles sao criados automaticamente ao salvar, no maximo um por hora.",
+	BackupsHint:          "j/k: navegar | Enter: escolher | Esc: cancelar",
+	BackupConfirm:        "Substituir os dados atuais por este backup? Os dados atuais tambem serao salvos como backup.",
+	BackupCurrent:        "Atual:  ",
+	BackupSelected:       "Backup: ",
+	StatusBackupRestored: "Backup %s restaurado",
+	StatusBackupFailed:   "Erro ao ler backups: %v",
+
+	// Encryption
+	CLIPassphrasePrompt:   "Senha: ",
+	CLINewPassphrase:      "Nova senha: ",
+	CLIConfirmPassphrase:  "Confirme a senha: ",
+	CLIWrongPassphrase:    "senha incorreta",
+	CLIEmptyPassphrase:    "a senha nao pode ser vazia",
+	CLIPassphraseMismatch: "as senhas nao conferem",
+	CLIAlreadyEncrypted:   "os dados ja estao criptografados",
+	CLINotEncrypted:       "os dados nao estao criptografados",
+	CLIEncrypted:          "Dados e backups criptografados. Guarde a senha: sem ela nao ha como recuperar as tarefas.\n",
+	CLIDecrypted:          "Dados e backups salvos sem criptografia.\n",
+	StatusWorkspaceLocked: "Workspace criptografado, abra com t7t -w %s",
+
+	// Git sync
+	SyncAdded:          "%d adicionada(s)",
+	SyncCompleted:      "%d concluida(s)",
+	SyncUpdated:        "%d alterada(s)",
+	SyncDeleted:        "%d excluida(s)",
+	SyncNoTaskChanges:  "Atualiza projetos e configuracoes",
+	SyncMergeMessage:   "Mescla tarefas do remoto",
+	SyncMergeConflicts: "Alteradas nos dois lados, mantida a versao mais recente:",
+	CLISyncCommitted:   "Commit: %s\n",
+	CLISyncPulled:      "Alteracoes do remoto aplicadas\n",
+	CLISyncMerged:      "Alteracoes locais e do remoto mescladas\n",
+	CLISyncConflicts:   "Alteradas nos dois lados, mantida a versao mais recente: %s\n",
+	CLISyncPushed:      "Enviado para o remoto\n",
+	CLISyncNoRemote:    "Nenhum remoto configurado, use t7t sync -remote URL\n",
+
+	// Merge
+	MergeTask:           "tarefa",
+	MergeProject:        "projeto",
+	CLIMergeAdded:       "+ %s %q: adicionado, so existe na outra copia (%s)\n",
+	CLIMergeKept:        "= %s %q: mantido, so existe aqui (%s)\n",
+	CLIMergeRemoved:     "- %s %q: removido, excluido na outra copia (%s)\n",
+	CLIMergeSurvived:    "! %s %q: mantido, alterado aqui depois de excluido na outra copia (%s)\n",
+	CLIMergeStayDeleted: "- %s %q: ignorado, excluido aqui (%s)\n",
+	CLIMergeRestored:    "! %s %q: restaurado, alterado na outra copia depois de excluido aqui (%s)\n",
+	CLIMergeTookField:   "~ %s %q: %s da outra copia, mais recente (%s)\n",
+	CLIMergeKeptField:   "= %s %q: %s mantido, mais recente aqui (%s)\n",
+	CLIMergeSummary:     "%d alteracoes, %d iguais nos dois lados\n",
+	CLIMergeDryRun:      "Simulacao, nada foi salvo\n",
+
+	// API server
+	CLIServeListening: "API disponivel em http://%s/api (Ctrl+C para parar)\n",
+	CLIServeToken:     "Envie o token de %s como Authorization: Bearer <token>\n",
+	CLIServeTokenEnv:  "Envie o token de T7T_API_TOKEN como Authorization: Bearer <token>\n",
+
+	// Daemon
+	CLIDaemonListening: "Daemon escutando em %s (Ctrl+C para parar)\n",
+	CLIDaemonRunning:   "um daemon ja esta rodando em %s",
+	CLITaskAdded:       "Tarefa adicionada: %s (%s)\n",
+	CLIListEmpty:       "Nenhuma tarefa\n",
+	CLIUnknownCategory: "categoria desconhecida: %s (use today, week, not_urgent ou general)",
+
+	// CalDAV
+	LabelDue:                "Prazo: ",
+	CLICalDAVNoURL:          "nenhuma colecao CalDAV configurada, use t7t caldav -url URL",
+	CLICalDAVPasswordPrompt: "Senha CalDAV: ",
+	CLICalDAVResult:         "Enviadas %d, recebidas %d, excluidas aqui %d, excluidas no servidor %d\n",
+	CLICalDAVConflicts:      "Alteradas nos dois lados, mantida a versao mais recente: %s\n",
+
+	// Import and export
+	LabelPriority:    "Prioridade: ",
+	CLIImported:      "Importadas %d tarefas, criados %d projetos\n",
+	CLIUnknownFormat: "formato desconhecido: %s",
+
+	// Markdown export
+	KeyExport:             "exportar",
+	HelpGeneralExport:     "Exportar a lista (ou os projetos) em markdown",
+	StatusExported:        "Exportado para %s",
+	StatusExportFailed:    "Falha ao exportar: %v",
+	StatusExportEncrypted: "Dados criptografados: use t7t export markdown para gerar uma copia em texto puro",
+	CLIUnknownGrouping:    "agrupamento desconhecido: %s (use category ou project)",
+
+	// Import preview
+	CLIImportProject:      "+ projeto %q\n",
+	CLIImportTaskProjects: "      projetos: %s\n",
+	CLIImportDryRun:       "Simulacao: %d tarefas e %d projetos seriam criados, nada foi salvo\n",
+
+	// CSV
+	CLIImportLineError:   "linha %d: %v\n",
+	CLIImportInvalidRows: "%d linhas invalidas, nada foi importado",
+	CLIBadPair:           "par invalido: %q (use nome=valor)",
+	CLIBadSeparator:      "separador invalido: %q (use um unico caractere)",
+
+	// Calendar export
+	CLIFeedEnabled:  "%s sera exportado de novo a cada alteracao\n",
+	CLIFeedDisabled: "O calendario nao sera mais exportado a cada alteracao\n",
+
+	// Import updates
+	CLIImportChanges:       "      muda: %s\n",
+	CLIImportUpdated:       "Atualizadas %d tarefas\n",
+	CLIImportDryRunUpdates: "%d tarefas seriam atualizadas\n",
+
+	// HTML report
+	ReportTitle:             "Status das tarefas",
+	ReportGenerated:         "Gerado em %s",
+	ReportDateFormat:        "02/01/2006",
+	ReportDateTimeFormat:    "02/01/2006 15:04",
+	ReportDue:               "prazo %s",
+	ReportNoOpenTasks:       "Nenhuma tarefa pendente.",
+	ReportRecentlyCompleted: "Concluidas nos ultimos %d dias",
+	ReportNoRecent:          "Nenhuma tarefa concluida no periodo.",
+
+	// Quick add
+	FormQuickAddHint:       "!week +projeto due:sex @contexto",
+	FormQuickAddNewProject: "(novo)",
+
+	// Save hook warnings
+	CLIFeedEncrypted:     "os dados estao criptografados, mas o arquivo .ics nao ficaria; use -plaintext para gera-lo mesmo assim",
+	WarningSaveHook:      "Aviso: dados salvos, mas: %v\n",
+	StatusSaveHookFailed: "Salvo, mas: %v",
+
 	// Error messages
 	ErrorInitStorage: "Erro ao inicializar armazenamento: %v\n",
 	ErrorRunApp:      "Erro ao executar aplicacao: %v\n",
@@ -401,6 +923,7 @@ var en = &Messages{
 	StatusTaskReopened:     "Task reopened",
 	StatusTaskMoved:        "Task moved to %s",
 	StatusTaskCreated:      "Task created",
+	StatusTaskNameMissing:  "Type a name besides !list, +project and due:",
 	StatusTaskUpdated:      "Task updated",
 	StatusProjectsAssoc:    "Projects associated",
 	StatusProjectCompleted: "Project completed",
@@ -457,6 +980,7 @@ var en = &Messages{
 	HintFormFields:     "Tab: switch fields | Ctrl+S: save | Esc: cancel",
 	HintProjectForm:    "Enter: confirm | Esc: cancel",
 	HintAssocProjects:  "Space: select | Enter: confirm | Esc: cancel",
+	HintDependencies:   "Space: toggle blocker | Enter: confirm | Esc: cancel",
 	HintCloseHelp:      "Press any key to close",
 	HintNoProjectAvail: "No projects available.\nCreate a project first (P).",
 
@@ -551,6 +1075,264 @@ var en = &Messages{
 	LanguageHint:    "j/k: navigate | Enter: select | Esc: cancel",
 	LanguageChanged: "Language changed",
 
+	// Review mode
+	ReviewTitleList:        "Review - %s",
+	ReviewTitleAll:         "Review - All lists",
+	ReviewProgress:         "Task %d of %d",
+	ReviewLabelList:        "List: ",
+	ReviewHint:             "Enter/k: keep | 1-4: move | x: complete | f: defer | d: delete | Esc: finish",
+	ReviewEmpty:            "No open tasks to review",
+	ReviewSummaryTitle:     "Review Summary",
+	ReviewSummaryReviewed:  "Reviewed: %d of %d",
+	ReviewSummaryKept:      "Kept: %d",
+	ReviewSummaryMoved:     "Moved: %d",
+	ReviewSummaryCompleted: "Completed: %d",
+	ReviewSummaryDeferred:  "Deferred: %d",
+	ReviewSummaryDeleted:   "Deleted: %d",
+	ReviewSummaryChanges:   "Changes:",
+	ReviewSummaryNoChanges: "(no changes)",
+	ReviewActionMoved:      "moved to %s",
+	ReviewActionCompleted:  "completed",
+	ReviewActionDeferred:   "deferred to %s",
+	ReviewActionDeleted:    "deleted",
+	ReviewSummaryHint:      "Press any key to go back",
+	ReviewFinished:         "Review finished",
+	KeyReview:              "review list",
+	KeyReviewAll:           "review all",
+	HelpReviewSection:      "Review",
+	HelpReviewList:         "Review current list (daily)",
+	HelpReviewAll:          "Review all lists (weekly)",
+
+	// Statistics
+	StatsTitle:       "Statistics",
+	StatsBackToTasks: "[S] Back to Tasks",
+	StatsThroughput:  "Completed per day (last %d days)",
+	StatsToday:       "Today: %d",
+	StatsThisWeek:    "This week: %d",
+	StatsLastWeek:    "Last week: %d",
+	StatsWeekly:      "Completed per week",
+	StatsOpenAge:     "Open tasks per list",
+	StatsOpenAgeLine: "%d open, average age %s",
+	StatsProjects:    "Project progress",
+	StatsNoProjects:  "(no projects)",
+	StatsDays:        "%dd",
+	StatsHours:       "%dh",
+	StatsDateFormat:  "01/02",
+	KeyStats:         "statistics",
+	HelpNavStats:     "Statistics screen",
+
+	// Time tracking
+	StatusTimerStarted: "Timer started",
+	StatusTimerStopped: "Timer stopped (%s)",
+	LabelTimeTracked:   "Time tracked: ",
+	KeyTimer:           "start/stop timer",
+	HelpTaskTimer:      "Start/stop task timer",
+
+	// Command line
+	CLIUsage:           "Usage:\n  t7t                          open the interface\n  t7t add [-category C] <name> add a task\n  t7t add - | t7t capture       add a task per line of standard input\n                               (!week, +project, @context, due:fri)\n  t7t list [-category C] [-all] list the open tasks\n  t7t daemon                   serve the data to the interface and command line\n  t7t timer start <task>       start the task timer (ID or name)\n  t7t timer stop               stop the running timer\n  t7t timer status             show the running timer\n  t7t timer report [-days N]   totals per task, project and day\n  t7t backup list              list the automatic backups\n  t7t backup restore <backup>  restore a backup\n  t7t encrypt                  encrypt the data with a passphrase\n  t7t decrypt                  store the data unencrypted again\n  t7t sync [-remote URL]       sync the data through a git repository\n  t7t caldav [-url URL] [-user NAME]\n                               sync the tasks with a CalDAV server\n  t7t merge [-dry-run] <file>  merge another copy of the data file\n  t7t serve [-addr ADDR]       serve a local HTTP/JSON API\n  t7t import <format> [-dry-run] <file>\n                               import tasks (todotxt, markdown, csv,\n                               taskwarrior, org)\n  t7t export <format> [-o FILE]\n                               export the tasks (todotxt, markdown, csv, ics,\n                               taskwarrior, org)\n  t7t report html [-o FILE]    write an HTML page with the task status\n\nOptions (before the command):\n  --data-dir DIR               use DIR as the data directory\n  -w, --workspace NAME         use the NAME workspace\n",
+	CLIUnknownCommand:  "unknown command: %s",
+	CLIMissingArgument: "missing argument: %s",
+	CLIExtraArgument:   "unexpected argument: %s",
+	CLITaskNotFound:    "no task matches %q",
+	CLITaskAmbiguous:   "%d tasks match %q, use the ID",
+	CLITimerStarted:    "Timer started: %s\n",
+	CLITimerStopped:    "Timer stopped: %s (%s)\n",
+	CLITimerNone:       "No timer running\n",
+	CLITimerRunning:    "Timer running: %s (%s)\n",
+	CLIReportTitle:     "Time tracked in the last %d days: %s\n",
+	CLIReportByTask:    "Per task:",
+	CLIReportByProject: "Per project:",
+	CLIReportByDay:     "Per day:",
+	CLIReportNoProject: "(no project)",
+	ErrorCommand:       "Error: %v\n",
+
+	// Focus mode
+	FocusTitle:           "Focus",
+	FocusPhaseWork:       "Work",
+	FocusPhaseShortBreak: "Short break",
+	FocusPhaseLongBreak:  "Long break",
+	FocusPaused:          "(paused)",
+	FocusPomodoros:       "Task pomodoros: %d",
+	FocusSession:         "This session: %d",
+	FocusSettings:        "Work %dm | Short break %dm | Long break %dm (every %d)",
+	FocusCelebrate:       "Celebrate when a pomodoro ends: %s",
+	FocusYes:             "yes",
+	FocusNo:              "no",
+	FocusHint:            "Space: start/pause | s: skip | r: restart | +/-: duration | c: celebrate | Esc: exit",
+	StatusPomodoroDone:   "Pomodoro done! Time for a break",
+	StatusBreakDone:      "Break over, back to work",
+	LabelPomodoros:       "Pomodoros: ",
+	KeyFocus:             "focus mode",
+	HelpTaskFocus:        "Focus mode (Pomodoro)",
+
+	// Dependencies
+	ModalDependencies:     "Blocking Tasks",
+	HintNoOtherTasks:      "No other tasks available.",
+	LabelBlocked:          "Blocked",
+	LabelBlockedBy:        "Blocked by:",
+	LabelBlocks:           "Blocks:",
+	StatusDependenciesSet: "Dependencies updated",
+	StatusDependencyCycle: "Dependency cycle detected, change discarded",
+	StatusUnblocked:       "Unblocked: %s",
+	KeyDependencies:       "blockers",
+	HelpTaskDeps:          "Set blocking tasks",
+
+	// Persistence errors
+	StatusSaveFailed:    "Failed to save: %v",
+	StatusSaveRecovered: "Changes saved",
+	BannerUnsaved:       "Unsaved changes: %v - retrying every %ds",
+	ModalUnsavedQuit:    "Unsaved Changes",
+	ConfirmUnsavedQuit:  "Your changes have not been saved yet:",
+	UnsavedRetry:        "retry saving and quit",
+	UnsavedDiscard:      "quit without saving",
+
+	// Live reload
+	StatusReloaded:        "Loaded changes made outside t7t",
+	StatusReloadConflicts: "Edited here and elsewhere, kept the newest version of: %s",
+	StatusReloadFailed:    "Failed to reload data: %v",
+
+	// Workspaces
+	KeyWorkspace:            "workspaces",
+	HelpGeneralWorkspace:    "Switch workspace",
+	ModalWorkspace:          "Workspaces",
+	ModalNewWorkspace:       "New Workspace",
+	FormWorkspaceName:       "Name:",
+	WorkspaceHint:           "j/k: navigate | Enter: open | a: new | Esc: cancel",
+	StatusWorkspaceSwitched: "Workspace: %s",
+	StatusWorkspaceUnsaved:  "There are unsaved changes, try again once they are saved",
+	StatusWorkspaceInvalid:  "Invalid workspace name: %q",
+	StatusWorkspaceFailed:   "Failed to open workspace: %v",
+	ErrorInvalidWorkspace:   "Invalid workspace name: %q\n",
+
+	// Backups
+	BackupCounts:         "%d tasks (%d open), %d projects",
+	CLIBackupNone:        "No backups yet\n",
+	CLIBackupNotFound:    "backup %q not found, see t7t backup list",
+	CLIBackupRestored:    "Restored %s: %s\n",
+	KeyBackups:           "backups",
+	HelpGeneralBackups:   "Restore a backup",
+	ModalBackups:         "Restore Backup",
+	BackupsEmpty:         "No backups yet. They are created automatically when saving, at most one per hour.",
+	BackupsHint:          "j/k: navigate | Enter: choose | Esc: cancel",
+	BackupConfirm:        "Replace the current data with this backup? The current data is backed up as well.",
+	BackupCurrent:        "Current: ",
+	BackupSelected:       "Backup:  ",
+	StatusBackupRestored: "Restored backup %s",
+	StatusBackupFailed:   "Failed to read backups: %v",
+
+	// Encryption
+	CLIPassphrasePrompt:   "Passphrase: ",
+	CLINewPassphrase:      "New passphrase: ",
+	CLIConfirmPassphrase:  "Confirm passphrase: ",
+	CLIWrongPassphrase:    "wrong passphrase",
+	CLIEmptyPassphrase:    "the passphrase can't be empty",
+	CLIPassphraseMismatch: "the passphrases don't match",
+	CLIAlreadyEncrypted:   "the data is already encrypted",
+	CLINotEncrypted:       "the data is not encrypted",
+	CLIEncrypted:          "Data and backups encrypted. Keep the passphrase safe: without it the tasks can't be recovered.\n",
+	CLIDecrypted:          "Data and backups are no longer encrypted.\n",
+	StatusWorkspaceLocked: "Encrypted workspace, open it with t7t -w %s",
+
+	// Git sync
+	SyncAdded:          "%d added",
+	SyncCompleted:      "%d completed",
+	SyncUpdated:        "%d updated",
+	SyncDeleted:        "%d deleted",
+	SyncNoTaskChanges:  "Update projects and settings",
+	SyncMergeMessage:   "Merge tasks from remote",
+	SyncMergeConflicts: "Changed on both sides, kept the newest version:",
+	CLISyncCommitted:   "Committed: %s\n",
+	CLISyncPulled:      "Applied changes from the remote\n",
+	CLISyncMerged:      "Merged local and remote changes\n",
+	CLISyncConflicts:   "Changed on both sides, kept the newest version: %s\n",
+	CLISyncPushed:      "Pushed to the remote\n",
+	CLISyncNoRemote:    "No remote configured, use t7t sync -remote URL\n",
+
+	// Merge
+	MergeTask:           "task",
+	MergeProject:        "project",
+	CLIMergeAdded:       "+ %s %q: added, only in the other copy (%s)\n",
+	CLIMergeKept:        "= %s %q: kept, only here (%s)\n",
+	CLIMergeRemoved:     "- %s %q: removed, deleted in the other copy (%s)\n",
+	CLIMergeSurvived:    "! %s %q: kept, changed here after it was deleted in the other copy (%s)\n",
+	CLIMergeStayDeleted: "- %s %q: skipped, deleted here (%s)\n",
+	CLIMergeRestored:    "! %s %q: restored, changed in the other copy after it was deleted here (%s)\n",
+	CLIMergeTookField:   "~ %s %q: %s from the other copy, newer (%s)\n",
+	CLIMergeKeptField:   "= %s %q: %s kept, newer here (%s)\n",
+	CLIMergeSummary:     "%d changes, %d identical on both sides\n",
+	CLIMergeDryRun:      "Dry run, nothing was saved\n",
+
+	// API server
+	CLIServeListening: "API listening on http://%s/api (Ctrl+C to stop)\n",
+	CLIServeToken:     "Send the token from %s as Authorization: Bearer <token>\n",
+	CLIServeTokenEnv:  "Send the token from T7T_API_TOKEN as Authorization: Bearer <token>\n",
+
+	// Daemon
+	CLIDaemonListening: "Daemon listening on %s (Ctrl+C to stop)\n",
+	CLIDaemonRunning:   "a daemon is already running on %s",
+	CLITaskAdded:       "Task added: %s (%s)\n",
+	CLIListEmpty:       "No tasks\n",
+	CLIUnknownCategory: "unknown category: %s (use today, week, not_urgent or general)",
+
+	// CalDAV
+	LabelDue:                "Due: ",
+	CLICalDAVNoURL:          "no CalDAV collection configured, use t7t caldav -url URL",
+	CLICalDAVPasswordPrompt: "CalDAV password: ",
+	CLICalDAVResult:         "Sent %d, received %d, deleted here %d, deleted on the server %d\n",
+	CLICalDAVConflicts:      "Changed on both sides, kept the newest version: %s\n",
+
+	// Import and export
+	LabelPriority:    "Priority: ",
+	CLIImported:      "Imported %d tasks, created %d projects\n",
+	CLIUnknownFormat: "unknown format: %s",
+
+	// Markdown export
+	KeyExport:             "export",
+	HelpGeneralExport:     "Export the lists (or the projects) as markdown",
+	StatusExported:        "Exported to %s",
+	StatusExportFailed:    "Export failed: %v",
+	StatusExportEncrypted: "The data is encrypted: use t7t export markdown for a plaintext copy",
+	CLIUnknownGrouping:    "unknown grouping: %s (use category or project)",
+
+	// Import preview
+	CLIImportProject:      "+ project %q\n",
+	CLIImportTaskProjects: "      projects: %s\n",
+	CLIImportDryRun:       "Dry run: %d tasks and %d projects would be created, nothing was saved\n",
+
+	// CSV
+	CLIImportLineError:   "line %d: %v\n",
+	CLIImportInvalidRows: "%d invalid rows, nothing was imported",
+	CLIBadPair:           "invalid pair: %q (use name=value)",
+	CLIBadSeparator:      "invalid separator: %q (use a single character)",
+
+	// Calendar export
+	CLIFeedEnabled:  "%s will be exported again on every change\n",
+	CLIFeedDisabled: "The calendar will no longer be exported on every change\n",
+
+	// Import updates
+	CLIImportChanges:       "      changes: %s\n",
+	CLIImportUpdated:       "Updated %d tasks\n",
+	CLIImportDryRunUpdates: "%d tasks would be updated\n",
+
+	// HTML report
+	ReportTitle:             "Task status",
+	ReportGenerated:         "Generated %s",
+	ReportDateFormat:        "Jan 2, 2006",
+	ReportDateTimeFormat:    "Jan 2, 2006 15:04",
+	ReportDue:               "due %s",
+	ReportNoOpenTasks:       "No open tasks.",
+	ReportRecentlyCompleted: "Completed in the last %d days",
+	ReportNoRecent:          "No tasks completed in this period.",
+
+	// Quick add
+	FormQuickAddHint:       "!week +project due:fri @context",
+	FormQuickAddNewProject: "(new)",
+
+	// Save hook warnings
+	CLIFeedEncrypted:     "the data is encrypted but the .ics file would not be; add -plaintext to write it anyway",
+	WarningSaveHook:      "Warning: data saved, but: %v\n",
+	StatusSaveHookFailed: "Saved, but: %v",
+
 	// Error messages
 	ErrorInitStorage: "Error initializing storage: %v\n",
 	ErrorRunApp:      "Error running application: %v\n",
@@ -564,18 +1346,21 @@ var en = &Messages{
 
 func init() {
 	messages = ptBR
-	loadSavedLanguage()
+	LoadSavedLanguage()
 }
 
 func getConfigPath() string {
-	homeDir, err := os.UserHomeDir()
+	configDir, err := config.ConfigDir()
 	if err != nil {
 		return ""
 	}
-	return filepath.Join(homeDir, ".t7t", "language.json")
+	return filepath.Join(configDir, "language.json")
 }
 
-func loadSavedLanguage() {
+// LoadSavedLanguage applies the language saved in the config directory. It
+// runs at startup and again once command line flags may have moved that
+// directory.
+func LoadSavedLanguage() {
 	configPath := getConfigPath()
 	if configPath == "" {
 		return
@@ -648,6 +1433,14 @@ func Get() *Messages {
 	return messages
 }
 
+// For returns the messages of a language, without switching to it.
+func For(lang Language) *Messages {
+	if lang == English {
+		return en
+	}
+	return ptBR
+}
+
 func AvailableLanguages() []Language {
 	return []Language{Portuguese, English}
 }
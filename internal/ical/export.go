@@ -0,0 +1,136 @@
+package ical
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"t7t/internal/model"
+)
+
+// calendarName is what calendar apps show for a subscribed export.
+const calendarName = "t7t"
+
+// Export writes every task as a VTODO, using the task ID as its UID so apps
+// subscribed to the file recognize the same task across exports. With events,
+// open tasks that have a due date also get an all-day VEVENT on that date, for
+// calendar apps that don't show to-dos.
+func Export(store *model.Store, w io.Writer, events bool) error {
+	now := time.Now()
+	cal := Calendar()
+	cal.Set("X-WR-CALNAME", calendarName, nil)
+	for _, task := range store.Tasks {
+		cal.Children = append(cal.Children, Todo(task, store.GetProjectNames(task.ProjectIDs), now))
+		if events && task.Due != nil && !task.Completed {
+			cal.Children = append(cal.Children, dueEvent(task, now))
+		}
+	}
+	return cal.Encode(w)
+}
+
+// dueEvent marks a task's due date, with a UID derived from the task's so it
+// doesn't clash with the VTODO.
+func dueEvent(task *model.Task, now time.Time) *Component {
+	c := New("VEVENT")
+	c.SetText("UID", task.ID+"-due")
+	c.SetTime("DTSTAMP", now)
+	c.SetTime("LAST-MODIFIED", task.UpdatedAt)
+	c.SetText("SUMMARY", task.Name)
+	c.SetText("DESCRIPTION", task.Description)
+	setDay(c, "DTSTART", *task.Due)
+	c.Set("TRANSP", "TRANSPARENT", nil)
+	return c
+}
+
+const feedFile = "ics-feed.json"
+
+// Feed is a calendar file exported again after every save, for calendar apps
+// subscribed to it. Its settings are kept next to the data file, apart from
+// the data, since the path only makes sense on this machine.
+type Feed struct {
+	Path   string `json:"path"`
+	Events bool   `json:"events,omitempty"`
+
+	// Plaintext allows writing the feed for encrypted data, which the
+	// calendar file doesn't keep encrypted.
+	Plaintext bool `json:"plaintext,omitempty"`
+}
+
+// ErrPlaintextFeed is reported instead of writing an unencrypted calendar
+// file for encrypted data without the user agreeing to it.
+var ErrPlaintextFeed = errors.New("the data is encrypted but the calendar file would not be")
+
+// LoadFeed reads the feed set up in dir, or returns nil if there is none.
+func LoadFeed(dir string) (*Feed, error) {
+	data, err := os.ReadFile(filepath.Join(dir, feedFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var feed Feed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// SaveFeed sets up the feed for the data in dir, or removes it when feed is
+// nil.
+func SaveFeed(dir string, feed *Feed) error {
+	path := filepath.Join(dir, feedFile)
+	if feed == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Write exports the store to the feed's file, replacing it in one step so a
+// calendar app never reads half of it.
+func (f *Feed) Write(store *model.Store) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), ".t7t-*.ics")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := Export(store, tmp, f.Events); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+// UpdateFeed is a save hook that rewrites the feed of the saved store, if it
+// has one.
+func UpdateFeed(store *model.Store, encrypted bool) error {
+	feed, err := LoadFeed(store.Dir())
+	if err != nil || feed == nil {
+		return err
+	}
+	if encrypted && !feed.Plaintext {
+		return fmt.Errorf("%s: %w", feed.Path, ErrPlaintextFeed)
+	}
+	if err := feed.Write(store); err != nil {
+		return fmt.Errorf("%s: %w", feed.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+// Package ical reads and writes iCalendar data (RFC 5545): components made of
+// properties, such as the VTODO items calendar apps use for tasks.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	localFormat    = "20060102T150405"
+	dateFormat     = "20060102"
+
+	// maxLineLength is the longest a content line may be, in octets, before
+	// it's folded.
+	maxLineLength = 75
+)
+
+var ErrMalformed = errors.New("malformed iCalendar data")
+
+// Property is a content line. Value is kept as written, escaped; use the
+// Text and SetText helpers of Component for text values.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component is a BEGIN/END block, such as VCALENDAR or VTODO.
+type Component struct {
+	Name     string
+	Props    []Property
+	Children []*Component
+}
+
+func New(name string) *Component {
+	return &Component{Name: name}
+}
+
+// Get returns the first property with the given name.
+func (c *Component) Get(name string) (Property, bool) {
+	for _, p := range c.Props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Property{}, false
+}
+
+// Set replaces every property with the given name by a single one.
+func (c *Component) Set(name, value string, params map[string]string) {
+	c.Del(name)
+	c.Add(name, value, params)
+}
+
+func (c *Component) Add(name, value string, params map[string]string) {
+	c.Props = append(c.Props, Property{Name: name, Params: params, Value: value})
+}
+
+func (c *Component) Del(name string) {
+	props := c.Props[:0]
+	for _, p := range c.Props {
+		if p.Name != name {
+			props = append(props, p)
+		}
+	}
+	c.Props = props
+}
+
+// Text returns the unescaped value of a text property.
+func (c *Component) Text(name string) string {
+	p, _ := c.Get(name)
+	return unescape(p.Value)
+}
+
+// SetText sets a text property, or removes it when value is empty.
+func (c *Component) SetText(name, value string) {
+	if value == "" {
+		c.Del(name)
+		return
+	}
+	c.Set(name, escape(value), nil)
+}
+
+// List returns the values of every property with the given name, split on
+// commas, as used by CATEGORIES and RELATED-TO.
+func (c *Component) List(name string) []string {
+	var values []string
+	for _, p := range c.Props {
+		if p.Name != name {
+			continue
+		}
+		for _, v := range splitUnescaped(p.Value) {
+			if v = strings.TrimSpace(unescape(v)); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// SetList sets a comma separated text list, or removes it when empty.
+func (c *Component) SetList(name string, values []string) {
+	if len(values) == 0 {
+		c.Del(name)
+		return
+	}
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = escape(v)
+	}
+	c.Set(name, strings.Join(escaped, ","), nil)
+}
+
+// Time parses a DATE or DATE-TIME property. Dates and floating times are
+// taken as local time, and TZID parameters are honored when the zone is
+// known.
+func (c *Component) Time(name string) (time.Time, bool) {
+	p, ok := c.Get(name)
+	if !ok {
+		return time.Time{}, false
+	}
+	loc := time.Local
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	if t, err := time.Parse(dateTimeFormat, p.Value); err == nil {
+		return t, true
+	}
+	for _, layout := range []string{localFormat, dateFormat} {
+		if t, err := time.ParseInLocation(layout, p.Value, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SetTime sets a DATE-TIME property in UTC.
+func (c *Component) SetTime(name string, t time.Time) {
+	c.Set(name, t.UTC().Format(dateTimeFormat), nil)
+}
+
+// SetDate sets a DATE property from the local date of t.
+func (c *Component) SetDate(name string, t time.Time) {
+	c.Set(name, t.Local().Format(dateFormat), map[string]string{"VALUE": "DATE"})
+}
+
+// Components returns the direct children with the given name.
+func (c *Component) Components(name string) []*Component {
+	var found []*Component
+	for _, child := range c.Children {
+		if child.Name == name {
+			found = append(found, child)
+		}
+	}
+	return found
+}
+
+// Encode writes the component with CRLF line endings, folding long lines.
+func (c *Component) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	c.encode(bw)
+	return bw.Flush()
+}
+
+func (c *Component) String() string {
+	var b strings.Builder
+	c.Encode(&b)
+	return b.String()
+}
+
+func (c *Component) encode(w *bufio.Writer) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Props {
+		line := p.Name
+		for _, k := range sortedKeys(p.Params) {
+			v := p.Params[k]
+			if strings.ContainsAny(v, ":;,") {
+				v = `"` + v + `"`
+			}
+			line += ";" + k + "=" + v
+		}
+		writeLine(w, line+":"+p.Value)
+	}
+	for _, child := range c.Children {
+		child.encode(w)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// writeLine folds a content line into chunks of at most maxLineLength octets
+// without splitting UTF-8 sequences.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// The leading space of a continuation line counts too.
+		limit = maxLineLength - 1
+	}
+	w.WriteString(line + "\r\n")
+}
+
+// Decode reads the first component in r, usually a VCALENDAR.
+func Decode(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack []*Component
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		switch p.Name {
+		case "BEGIN":
+			c := New(strings.ToUpper(p.Value))
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, c)
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(p.Value) {
+				return nil, fmt.Errorf("%w: unexpected END:%s", ErrMalformed, p.Value)
+			}
+			if len(stack) == 1 {
+				return stack[0], nil
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%w: property outside a component", ErrMalformed)
+			}
+			c := stack[len(stack)-1]
+			c.Props = append(c.Props, p)
+		}
+	}
+	return nil, fmt.Errorf("%w: missing END", ErrMalformed)
+}
+
+// unfold joins continuation lines, which start with a space or tab.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits a content line into name, parameters and value. Colons
+// and semicolons inside quoted parameter values don't count.
+func parseLine(line string) (Property, error) {
+	var p Property
+	inQuotes := false
+	start := 0
+	var parts []string
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ';':
+			if !inQuotes {
+				parts = append(parts, line[start:i])
+				start = i + 1
+			}
+		case ':':
+			if !inQuotes {
+				parts = append(parts, line[start:i])
+				p.Name = strings.ToUpper(parts[0])
+				for _, param := range parts[1:] {
+					k, v, _ := strings.Cut(param, "=")
+					if p.Params == nil {
+						p.Params = make(map[string]string)
+					}
+					p.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+				}
+				p.Value = line[i+1:]
+				return p, nil
+			}
+		}
+	}
+	return p, fmt.Errorf("%w: %q", ErrMalformed, line)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitUnescaped splits a list value on the commas that aren't escaped.
+func splitUnescaped(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
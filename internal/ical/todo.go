@@ -0,0 +1,115 @@
+package ical
+
+import (
+	"time"
+
+	"t7t/internal/model"
+)
+
+const (
+	// CategoryProp carries the t7t category, which has no iCalendar
+	// counterpart. Project names go to CATEGORIES instead, where other
+	// calendar apps show them.
+	CategoryProp = "X-T7T-CATEGORY"
+
+	prodID = "-//t7t//t7t//EN"
+)
+
+// Calendar wraps components in a VCALENDAR.
+func Calendar(components ...*Component) *Component {
+	cal := New("VCALENDAR")
+	cal.Set("VERSION", "2.0", nil)
+	cal.Set("PRODID", prodID, nil)
+	cal.Children = components
+	return cal
+}
+
+// Todo builds the VTODO for a task, given the names of its projects.
+func Todo(task *model.Task, projectNames []string, now time.Time) *Component {
+	c := New("VTODO")
+	c.SetText("UID", task.ID)
+	c.SetTime("DTSTAMP", now)
+	c.SetTime("CREATED", task.CreatedAt)
+	c.SetTime("LAST-MODIFIED", task.UpdatedAt)
+	c.SetText("SUMMARY", task.Name)
+	c.SetText("DESCRIPTION", task.Description)
+	c.Set(CategoryProp, string(task.Category), nil)
+	c.SetList("CATEGORIES", projectNames)
+	if task.Due != nil {
+		setDay(c, "DUE", *task.Due)
+	}
+	if task.Completed {
+		c.Set("STATUS", "COMPLETED", nil)
+		c.Set("PERCENT-COMPLETE", "100", nil)
+		if task.CompletedAt != nil {
+			c.SetTime("COMPLETED", *task.CompletedAt)
+		}
+	} else {
+		c.Set("STATUS", "NEEDS-ACTION", nil)
+	}
+	return c
+}
+
+// setDay writes times without a time of day, such as most due dates, as DATE
+// values, the way calendar apps create them.
+func setDay(c *Component, name string, t time.Time) {
+	local := t.Local()
+	if local.Hour() == 0 && local.Minute() == 0 && local.Second() == 0 {
+		c.SetDate(name, local)
+	} else {
+		c.SetTime(name, t)
+	}
+}
+
+// NewTask creates the task for a VTODO found elsewhere, keeping its UID as
+// the task ID. The rest is filled in by ApplyTodo.
+func NewTask(c *Component) *model.Task {
+	task := model.NewTask(c.Text("SUMMARY"), c.Text("DESCRIPTION"), model.CategoryToday)
+	if uid := c.Text("UID"); uid != "" {
+		task.ID = uid
+	}
+	if created, ok := c.Time("CREATED"); ok {
+		task.CreatedAt = created
+	}
+	return task
+}
+
+// ApplyTodo updates a task with what a VTODO says about it, through the
+// task's setters so each changed field is recorded. It returns the project
+// names for the caller to resolve.
+func ApplyTodo(task *model.Task, c *Component) []string {
+	name, description := c.Text("SUMMARY"), c.Text("DESCRIPTION")
+	if name != "" && (name != task.Name || description != task.Description) {
+		task.Update(name, description)
+	}
+
+	if category := model.Category(c.Text(CategoryProp)); category.Valid() && category != task.Category {
+		task.SetCategory(category)
+	}
+
+	if completed := c.Text("STATUS") == "COMPLETED"; completed != task.Completed {
+		task.ToggleComplete()
+		if at, ok := c.Time("COMPLETED"); ok && completed {
+			task.CompletedAt = &at
+		}
+	}
+
+	due, hasDue := c.Time("DUE")
+	switch {
+	case hasDue && (task.Due == nil || !task.Due.Equal(due)):
+		task.SetDue(&due)
+	case !hasDue && task.Due != nil:
+		task.SetDue(nil)
+	}
+
+	return c.List("CATEGORIES")
+}
+
+// LastModified is when the VTODO last changed, falling back to its DTSTAMP.
+func LastModified(c *Component) time.Time {
+	if t, ok := c.Time("LAST-MODIFIED"); ok {
+		return t
+	}
+	t, _ := c.Time("DTSTAMP")
+	return t
+}
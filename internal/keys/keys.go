@@ -15,6 +15,7 @@ type KeyMap struct {
 	NextTab  key.Binding
 	PrevTab  key.Binding
 	Projects key.Binding
+	Stats    key.Binding
 
 	// Task operations
 	NewTask        key.Binding
@@ -24,6 +25,13 @@ type KeyMap struct {
 	CompleteTask   key.Binding
 	DeleteDone     key.Binding
 	AssocProjects  key.Binding
+	Dependencies   key.Binding
+	Timer          key.Binding
+	Focus          key.Binding
+
+	// Review
+	Review    key.Binding
+	ReviewAll key.Binding
 
 	// Move task
 	MoveToday     key.Binding
@@ -38,12 +46,15 @@ type KeyMap struct {
 	CompleteProject key.Binding
 
 	// General
-	Help     key.Binding
-	Quit     key.Binding
-	Enter    key.Binding
-	Escape   key.Binding
-	SaveForm key.Binding
-	Language key.Binding
+	Help      key.Binding
+	Quit      key.Binding
+	Enter     key.Binding
+	Escape    key.Binding
+	SaveForm  key.Binding
+	Language  key.Binding
+	Workspace key.Binding
+	Backups   key.Binding
+	Export    key.Binding
 }
 
 var Keys KeyMap
@@ -86,6 +97,11 @@ func UpdateKeybindings() {
 			key.WithHelp("P", msg.KeyProjects),
 		),
 
+		Stats: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", msg.KeyStats),
+		),
+
 		// Task operations
 		NewTask: key.NewBinding(
 			key.WithKeys("a"),
@@ -116,6 +132,30 @@ func UpdateKeybindings() {
 			key.WithHelp("p", msg.KeyAssocProjects),
 		),
 
+		Dependencies: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", msg.KeyDependencies),
+		),
+		Timer: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", msg.KeyTimer),
+		),
+
+		Focus: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", msg.KeyFocus),
+		),
+
+		// Review
+		Review: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", msg.KeyReview),
+		),
+		ReviewAll: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", msg.KeyReviewAll),
+		),
+
 		// Move task
 		MoveToday: key.NewBinding(
 			key.WithKeys("1"),
@@ -177,6 +217,18 @@ func UpdateKeybindings() {
 			key.WithKeys("L"),
 			key.WithHelp("L", msg.KeyLanguage),
 		),
+		Workspace: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", msg.KeyWorkspace),
+		),
+		Backups: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", msg.KeyBackups),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", msg.KeyExport),
+		),
 	}
 }
 
@@ -186,11 +238,12 @@ func (k KeyMap) ShortHelp() []key.Binding {
 
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.NextTab, k.PrevTab, k.Projects},
+		{k.Up, k.Down, k.NextTab, k.PrevTab, k.Projects, k.Stats},
 		{k.NewTask, k.NewTaskGeneral, k.EditTask, k.DeleteTask},
-		{k.CompleteTask, k.DeleteDone, k.AssocProjects},
+		{k.CompleteTask, k.DeleteDone, k.AssocProjects, k.Dependencies, k.Timer, k.Focus},
+		{k.Review, k.ReviewAll},
 		{k.MoveToday, k.MoveWeek, k.MoveNotUrgent, k.MoveGeneral},
 		{k.NewProject, k.EditProject, k.CompleteProject},
-		{k.Help, k.Language, k.Quit, k.Escape},
+		{k.Help, k.Language, k.Workspace, k.Backups, k.Export, k.Quit, k.Escape},
 	}
 }
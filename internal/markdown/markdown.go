@@ -0,0 +1,107 @@
+// Package markdown writes tasks as GitHub-style markdown checklists, the kind
+// that can be pasted into standup notes and pull request descriptions.
+package markdown
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+// Grouping decides what the sections of a document are.
+type Grouping int
+
+const (
+	ByCategory Grouping = iota
+	ByProject
+)
+
+// ParseGrouping reads a grouping given by name, as on the command line.
+func ParseGrouping(name string) (Grouping, bool) {
+	switch name {
+	case "category":
+		return ByCategory, true
+	case "project":
+		return ByProject, true
+	}
+	return 0, false
+}
+
+// Export writes a section per category or per project, leaving out the empty
+// ones. Tasks are listed with their descriptions nested underneath and, when
+// grouped by category, the names of their projects.
+func Export(store *model.Store, w io.Writer, by Grouping) error {
+	bw := bufio.NewWriter(w)
+	first := true
+	section := func(title string, tasks []*model.Task, withProjects bool) {
+		if len(tasks) == 0 {
+			return
+		}
+		if !first {
+			bw.WriteString("\n")
+		}
+		first = false
+		bw.WriteString("## " + title + "\n\n")
+		for _, task := range tasks {
+			var projects []string
+			if withProjects {
+				projects = store.GetProjectNames(task.ProjectIDs)
+			}
+			writeTask(bw, task, projects)
+		}
+	}
+
+	switch by {
+	case ByProject:
+		for _, project := range store.GetProjects() {
+			var tasks []*model.Task
+			for _, task := range store.Tasks {
+				if task.HasProject(project.ID) {
+					tasks = append(tasks, task)
+				}
+			}
+			section(project.Name, tasks, false)
+		}
+		var loose []*model.Task
+		for _, task := range store.Tasks {
+			if len(store.GetProjectNames(task.ProjectIDs)) == 0 {
+				loose = append(loose, task)
+			}
+		}
+		section(i18n.Get().CLIReportNoProject, loose, false)
+	default:
+		for _, category := range model.Categories {
+			section(model.CategoryString(category), store.GetTasksByCategory(category), true)
+		}
+	}
+	return bw.Flush()
+}
+
+// writeTask writes a checklist item, with the project names in parentheses
+// at the end and the description indented so it stays part of the item.
+func writeTask(w *bufio.Writer, task *model.Task, projects []string) {
+	box := "[ ]"
+	if task.Completed {
+		box = "[x]"
+	}
+	w.WriteString("- " + box + " " + task.Name)
+	if len(projects) > 0 {
+		w.WriteString(" _(" + strings.Join(projects, ", ") + ")_")
+	}
+	w.WriteString("\n")
+
+	description := strings.TrimRight(task.Description, " \n")
+	if description == "" {
+		return
+	}
+	for _, line := range strings.Split(description, "\n") {
+		if strings.TrimSpace(line) == "" {
+			w.WriteString("\n")
+			continue
+		}
+		w.WriteString("  " + line + "\n")
+	}
+}
@@ -0,0 +1,143 @@
+package markdown
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+// importCategory is where items go until a heading names a category.
+const importCategory = model.CategoryGeneral
+
+var (
+	headingRegex  = regexp.MustCompile(`^#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+	itemRegex     = regexp.MustCompile(`^[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	projectsRegex = regexp.MustCompile(`\s+_\(([^)]*)\)_$`)
+)
+
+// Parse reads markdown checklists into a batch of tasks. A heading naming a
+// category, by key or by label in any language, puts the items below it in
+// that category; any other heading names a project, created when it doesn't
+// exist yet, for the items below it. Items may end with a list of projects in
+// parentheses, as Export writes them, and the indented text that follows an
+// item becomes its description.
+func Parse(store *model.Store, r io.Reader) (*model.Batch, error) {
+	p := &parser{store: store, batch: &model.Batch{}, category: importCategory}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		p.line(strings.TrimRight(strings.ReplaceAll(scanner.Text(), "\t", "    "), " \r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	p.flush()
+	return p.batch, nil
+}
+
+type parser struct {
+	store    *model.Store
+	batch    *model.Batch
+	category model.Category
+	inFence  bool
+
+	// project names the project of the heading above, created only once an
+	// item belongs to it.
+	project string
+
+	// The item being read, the indent of its bullet and the lines of its
+	// description so far.
+	task        *model.Task
+	indent      int
+	description []string
+}
+
+func (p *parser) line(line string) {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := len(line) - len(trimmed)
+
+	if p.task != nil && (trimmed == "" || indent > p.indent) {
+		p.description = append(p.description, line[min(indent, p.indent+2):])
+		return
+	}
+	p.flush()
+
+	if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+		p.inFence = !p.inFence
+		return
+	}
+	if p.inFence {
+		return
+	}
+
+	if m := headingRegex.FindStringSubmatch(line); m != nil {
+		p.heading(m[1])
+		return
+	}
+	if m := itemRegex.FindStringSubmatch(trimmed); m != nil {
+		p.item(m[1] != " ", m[2], indent)
+	}
+}
+
+func (p *parser) heading(title string) {
+	if category, ok := model.ParseCategory(title); ok {
+		p.category = category
+		p.project = ""
+		return
+	}
+	for _, lang := range i18n.AvailableLanguages() {
+		if strings.EqualFold(title, i18n.For(lang).CLIReportNoProject) {
+			p.project = ""
+			return
+		}
+	}
+	p.project = title
+}
+
+func (p *parser) item(completed bool, text string, indent int) {
+	var projects []*model.Project
+	if p.project != "" {
+		projects = append(projects, p.batch.Project(p.store, p.project))
+	}
+	if m := projectsRegex.FindStringSubmatchIndex(text); m != nil {
+		for _, name := range strings.Split(text[m[2]:m[3]], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				projects = append(projects, p.batch.Project(p.store, name))
+			}
+		}
+		text = text[:m[0]]
+	}
+
+	name := strings.TrimSpace(text)
+	if name == "" {
+		return
+	}
+	task := model.NewTask(name, "", p.category)
+	for _, project := range projects {
+		if !task.HasProject(project.ID) {
+			task.ProjectIDs = append(task.ProjectIDs, project.ID)
+		}
+	}
+	if completed {
+		now := time.Now()
+		task.Completed = true
+		task.CompletedAt = &now
+	}
+	p.task, p.indent = task, indent
+}
+
+// flush finishes the item being read, if any.
+func (p *parser) flush() {
+	if p.task == nil {
+		return
+	}
+	p.task.Description = strings.Trim(strings.Join(p.description, "\n"), "\n")
+	p.batch.Tasks = append(p.batch.Tasks, p.task)
+	p.task, p.description = nil, nil
+}
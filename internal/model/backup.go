@@ -0,0 +1,216 @@
+package model
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	backupDirName    = "backups"
+	backupPrefix     = "data-"
+	backupTimeFormat = "20060102-150405"
+	// Names carry milliseconds so snapshots taken within the same second
+	// don't overwrite each other. Parsing with backupTimeFormat accepts
+	// both, as well as names written before milliseconds were added.
+	backupNameFormat = "20060102-150405.000"
+
+	// Retention: the newest snapshot of each of the last hourlyBackups hours
+	// and of each of the last dailyBackups days is kept.
+	hourlyBackups = 24
+	dailyBackups  = 14
+)
+
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Backup is a snapshot of the data file kept in the backups directory.
+type Backup struct {
+	Name     string
+	Time     time.Time
+	Tasks    int
+	Open     int
+	Projects int
+	path     string
+}
+
+func (s *Store) backupDir() string {
+	return filepath.Join(s.Dir(), backupDirName)
+}
+
+// listBackups returns the snapshots newest first, without reading them.
+func (s *Store) listBackups() ([]Backup, error) {
+	entries, err := os.ReadDir(s.backupDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), ".json")
+		t, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{
+			Name: strings.TrimSuffix(name, ".json"),
+			Time: t,
+			path: filepath.Join(s.backupDir(), name),
+		})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// Backups returns the available snapshots newest first, with the number of
+// tasks and projects in each so they can be previewed before restoring.
+func (s *Store) Backups() ([]Backup, error) {
+	backups, err := s.listBackups()
+	if err != nil {
+		return nil, err
+	}
+	for i := range backups {
+		if snapshot, err := s.readBackup(backups[i].path); err == nil {
+			backups[i].Tasks = len(snapshot.Tasks)
+			backups[i].Projects = len(snapshot.Projects)
+			for _, t := range snapshot.Tasks {
+				if !t.Completed {
+					backups[i].Open++
+				}
+			}
+		}
+	}
+	return backups, nil
+}
+
+func (s *Store) readBackup(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Store
+	if err := s.decode(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// backup keeps a snapshot of freshly saved data, at most one per hour, and
+// prunes the ones that fell out of the retention window. Backups are best
+// effort and never make a save fail.
+func (s *Store) backup(data []byte, now time.Time, force bool) error {
+	backups, err := s.listBackups()
+	if err != nil {
+		return err
+	}
+	if !force && len(backups) > 0 && backups[0].Time.Truncate(time.Hour).Equal(now.Truncate(time.Hour)) {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.backupDir(), 0755); err != nil {
+		return err
+	}
+	name := backupPrefix + now.Format(backupNameFormat)
+	path := filepath.Join(s.backupDir(), name+".json")
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return err
+	}
+
+	backups = append([]Backup{{Name: name, Time: now, path: path}}, backups...)
+	for _, b := range expiredBackups(backups) {
+		os.Remove(b.path)
+	}
+	return nil
+}
+
+// expiredBackups returns the snapshots, given newest first, that are not the
+// newest one of a retained hour or day.
+func expiredBackups(backups []Backup) []Backup {
+	hours := make(map[string]bool)
+	days := make(map[string]bool)
+	var expired []Backup
+
+	for _, b := range backups {
+		keep := false
+		if hour := b.Time.Format("2006010215"); !hours[hour] && len(hours) < hourlyBackups {
+			hours[hour] = true
+			keep = true
+		}
+		if day := b.Time.Format("20060102"); !days[day] && len(days) < dailyBackups {
+			days[day] = true
+			keep = true
+		}
+		if !keep {
+			expired = append(expired, b)
+		}
+	}
+	return expired
+}
+
+// FindBackup looks a snapshot up by name, with or without the .json suffix.
+func (s *Store) FindBackup(name string) (*Backup, error) {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ".json")
+	backups, err := s.Backups()
+	if err != nil {
+		return nil, err
+	}
+	for i := range backups {
+		if backups[i].Name == name {
+			return &backups[i], nil
+		}
+	}
+	return nil, ErrBackupNotFound
+}
+
+// RestoreBackup replaces the tasks, projects and settings with the contents of
+// a snapshot. The current data is backed up first so a restore can be undone.
+// Deletions made since the snapshot are remembered, except for the items the
+// restore brings back, so a later merge or sync doesn't resurrect them.
+func (s *Store) RestoreBackup(b *Backup) error {
+	snapshot, err := s.readBackup(b.path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if data, err := s.encode(); err == nil {
+		if err := s.backup(data, time.Now(), true); err != nil {
+			return err
+		}
+	}
+
+	s.Tasks = snapshot.Tasks
+	s.Projects = snapshot.Projects
+	restored := make(map[string]bool, len(snapshot.Tasks)+len(snapshot.Projects))
+	for _, t := range snapshot.Tasks {
+		restored[t.ID] = true
+	}
+	for _, p := range snapshot.Projects {
+		restored[p.ID] = true
+	}
+	s.Deleted = slices.DeleteFunc(mergeTombstones(s.Deleted, snapshot.Deleted), func(t Tombstone) bool {
+		return restored[t.ID]
+	})
+	s.Settings = snapshot.Settings
+	if s.Tasks == nil {
+		s.Tasks = []*Task{}
+	}
+	if s.Projects == nil {
+		s.Projects = []*Project{}
+	}
+	return s.write()
+}
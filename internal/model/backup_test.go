@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestoreBackupKeepsLaterDeletions(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := NewTask("Kept", "", CategoryToday)
+	if err := store.AddTask(kept); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := store.Backups()
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups %v, %v; want the one taken on save", backups, err)
+	}
+
+	later := NewTask("Added later", "", CategoryToday)
+	if err := store.AddTask(later); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteTask(later.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteTask(kept.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RestoreBackup(&backups[0]); err != nil {
+		t.Fatal(err)
+	}
+	if store.GetTask(kept.ID) == nil {
+		t.Fatal("restore didn't bring the task back")
+	}
+	deleted := tombstoneTimes(store.Deleted)
+	if _, ok := deleted[kept.ID]; ok {
+		t.Error("restored task still has a tombstone")
+	}
+	if _, ok := deleted[later.ID]; !ok {
+		t.Error("restore forgot a deletion made after the snapshot")
+	}
+}
+
+func TestBackupsInSameSecondGetOwnNames(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 19, 15, 4, 5, 0, time.Local)
+	var names []string
+	for i := range 2 {
+		if err := store.backup([]byte("{}"), now.Add(time.Duration(i)*time.Millisecond), true); err != nil {
+			t.Fatal(err)
+		}
+		backups, err := store.listBackups()
+		if err != nil || len(backups) == 0 {
+			t.Fatalf("backups %v, %v", backups, err)
+		}
+		names = append(names, backups[0].Name)
+	}
+	if names[0] == names[1] {
+		t.Errorf("both backups named %s", names[0])
+	}
+	if _, err := store.FindBackup(names[1]); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// Batch holds new projects and tasks read from elsewhere, such as an imported
+// file, so they can be shown before they're added with Store.AddBatch.
+type Batch struct {
+	Projects []*Project
+	Tasks    []*Task
+
+	// Updates are tasks read with the ID of a task in the store. Adding the
+	// batch copies what differs onto that task.
+	Updates []*Task
+}
+
+// Update records a task read with the ID of a task in the store, unless
+// nothing about it changed.
+func (b *Batch) Update(store *Store, task *Task) {
+	if existing := store.GetTask(task.ID); existing != nil && len(Changes(existing, task)) > 0 {
+		b.Updates = append(b.Updates, task)
+	}
+}
+
+// Changes names the fields an update would change on a task, by their JSON
+// names.
+func Changes(task, update *Task) []string {
+	var fields []string
+	if update.Name != task.Name {
+		fields = append(fields, "name")
+	}
+	if update.Description != task.Description {
+		fields = append(fields, "description")
+	}
+	if update.Category != task.Category {
+		fields = append(fields, "category")
+	}
+	if update.Completed != task.Completed {
+		fields = append(fields, "completed")
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(update.ProjectIDs)), slices.Sorted(slices.Values(task.ProjectIDs))) {
+		fields = append(fields, "project_ids")
+	}
+	if (update.Due == nil) != (task.Due == nil) || update.Due != nil && !update.Due.Equal(*task.Due) {
+		fields = append(fields, "due")
+	}
+	if update.Priority != task.Priority {
+		fields = append(fields, "priority")
+	}
+	return fields
+}
+
+// apply copies an update onto a task through its setters, so each change is
+// recorded for merges.
+func apply(task, update *Task) {
+	for _, field := range Changes(task, update) {
+		switch field {
+		case "name", "description":
+			task.Update(update.Name, update.Description)
+		case "category":
+			task.SetCategory(update.Category)
+		case "completed":
+			task.ToggleComplete()
+			if task.Completed && update.CompletedAt != nil {
+				task.CompletedAt = update.CompletedAt
+			}
+		case "project_ids":
+			task.SetProjects(update.ProjectIDs)
+		case "due":
+			task.SetDue(update.Due)
+		case "priority":
+			task.SetPriority(update.Priority)
+		}
+	}
+}
+
+// Project returns the project with the given name, ignoring case, from the
+// store or from the ones the batch creates. When neither has it, the batch
+// creates it.
+func (b *Batch) Project(store *Store, name string) *Project {
+	if p := store.ProjectByName(name); p != nil {
+		return p
+	}
+	for _, p := range b.Projects {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	p := NewProject(name)
+	b.Projects = append(b.Projects, p)
+	return p
+}
+
+// ProjectNames returns the names of a task's projects, including the ones
+// the batch creates.
+func (b *Batch) ProjectNames(store *Store, task *Task) []string {
+	var names []string
+	for _, id := range task.ProjectIDs {
+		if p := store.GetProject(id); p != nil {
+			names = append(names, p.Name)
+			continue
+		}
+		for _, p := range b.Projects {
+			if p.ID == id {
+				names = append(names, p.Name)
+			}
+		}
+	}
+	return names
+}
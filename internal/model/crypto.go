@@ -0,0 +1,288 @@
+package model
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	encryptedFormat = 1
+	keyLength       = 32
+
+	// scrypt cost parameters, as recommended for interactive logins.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	ErrPassphraseRequired = errors.New("passphrase required")
+	ErrWrongPassphrase    = errors.New("wrong passphrase")
+	ErrStoreLocked        = errors.New("store is locked")
+	ErrNotEncrypted       = errors.New("store is not encrypted")
+	ErrAlreadyEncrypted   = errors.New("store is already encrypted")
+
+	// ErrDifferentKey is returned for data sealed under another salt, such
+	// as a copy of the store encrypted separately on another machine. The
+	// passphrase may be the same, but the key derived from it isn't.
+	ErrDifferentKey = errors.New("data is encrypted with a different key")
+
+	// ErrBadKDFParameters is returned for a data file asking for scrypt
+	// costs t7t never writes, which could make unlocking take forever or
+	// exhaust memory.
+	ErrBadKDFParameters = errors.New("data file has invalid key derivation parameters")
+)
+
+// encryptedFile is the on-disk format of an encrypted store: the JSON data
+// sealed with AES-256-GCM under a key derived from the passphrase with scrypt.
+type encryptedFile struct {
+	Format     int    `json:"t7t_encrypted"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// encryption holds the derived key. The passphrase itself is never kept.
+type encryption struct {
+	key  []byte
+	salt []byte
+}
+
+func parseEncrypted(data []byte) (*encryptedFile, bool) {
+	if !bytes.Contains(data, []byte(`"t7t_encrypted"`)) {
+		return nil, false
+	}
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Format == 0 {
+		return nil, false
+	}
+	return &file, true
+}
+
+// validKDF reports whether the scrypt parameters read from a data file are
+// ones t7t could have written: powers of two for N, and nothing costlier than
+// the defaults.
+func (f *encryptedFile) validKDF() bool {
+	return f.N > 1 && f.N <= scryptN && f.N&(f.N-1) == 0 &&
+		f.R >= 1 && f.R <= scryptR &&
+		f.P >= 1 && f.P <= scryptP
+}
+
+func deriveKey(passphrase string, salt []byte, n, r, p int) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, n, r, p, keyLength)
+}
+
+func newEncryption(passphrase string) (*encryption, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	return &encryption{key: key, salt: salt}, nil
+}
+
+func (e *encryption) seal(plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(e.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(encryptedFile{
+		Format:     encryptedFormat,
+		KDF:        "scrypt",
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       e.salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+}
+
+func (e *encryption) open(file *encryptedFile) ([]byte, error) {
+	if !bytes.Equal(file.Salt, e.salt) {
+		return nil, ErrDifferentKey
+	}
+	gcm, err := newGCM(e.key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encode serializes the store, encrypting it when a key is set.
+func (s *Store) encode() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil || s.crypt == nil {
+		return data, err
+	}
+	return s.crypt.seal(data)
+}
+
+// decode parses data read from the data file or a backup into v, decrypting
+// it first if needed. Plain JSON is always accepted.
+func (s *Store) decode(data []byte, v any) error {
+	return decodeWith(s.crypt, data, v)
+}
+
+func decodeWith(crypt *encryption, data []byte, v any) error {
+	if file, ok := parseEncrypted(data); ok {
+		if crypt == nil {
+			return ErrPassphraseRequired
+		}
+		plaintext, err := crypt.open(file)
+		if err != nil {
+			return err
+		}
+		data = plaintext
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (s *Store) Locked() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.locked
+}
+
+func (s *Store) Encrypted() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.locked || s.crypt != nil
+}
+
+// Unlock derives the key for an encrypted data file and loads it. The
+// decrypted data only ever lives in memory.
+func (s *Store) Unlock(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	file, ok := parseEncrypted(data)
+	if !ok {
+		return ErrNotEncrypted
+	}
+	if file.KDF != "scrypt" {
+		return ErrWrongPassphrase
+	}
+	if !file.validKDF() {
+		return ErrBadKDFParameters
+	}
+	key, err := deriveKey(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return err
+	}
+
+	crypt := &encryption{key: key, salt: file.Salt}
+	plaintext, err := crypt.open(file)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(plaintext, s); err != nil {
+		return err
+	}
+	s.crypt = crypt
+	s.locked = false
+	s.snapshot()
+	return nil
+}
+
+// Encrypt switches the store to the encrypted format, re-writing the data
+// file and every backup with a key derived from passphrase.
+func (s *Store) Encrypt(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locked || s.crypt != nil {
+		return ErrAlreadyEncrypted
+	}
+	crypt, err := newEncryption(passphrase)
+	if err != nil {
+		return err
+	}
+	return s.recode(crypt)
+}
+
+// Decrypt switches an unlocked store back to plain JSON, backups included.
+func (s *Store) Decrypt() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locked {
+		return ErrStoreLocked
+	}
+	if s.crypt == nil {
+		return ErrNotEncrypted
+	}
+	return s.recode(nil)
+}
+
+// recode rewrites the data file and then the backups using crypt, which is
+// nil for plain JSON.
+func (s *Store) recode(crypt *encryption) error {
+	backups, err := s.listBackups()
+	if err != nil {
+		return err
+	}
+
+	previous := s.crypt
+	s.crypt = crypt
+	if err := s.write(); err != nil {
+		s.crypt = previous
+		return err
+	}
+
+	for _, b := range backups {
+		data, err := os.ReadFile(b.path)
+		if err != nil {
+			return err
+		}
+		var snapshot Store
+		if err := decodeWith(previous, data, &snapshot); err != nil {
+			return err
+		}
+		data, err = json.MarshalIndent(&snapshot, "", "  ")
+		if err == nil && crypt != nil {
+			data, err = crypt.seal(data)
+		}
+		if err == nil {
+			err = writeFileAtomic(b.path, data, 0644)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
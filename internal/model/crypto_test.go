@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnlockRejectsCostlyKDFParameters(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Encrypt("secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tamper := range []func(*encryptedFile){
+		func(f *encryptedFile) { f.N = 1 << 30 },
+		func(f *encryptedFile) { f.N = scryptN - 1 },
+		func(f *encryptedFile) { f.R = 1 << 20 },
+		func(f *encryptedFile) { f.P = 0 },
+	} {
+		data, err := os.ReadFile(store.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, ok := parseEncrypted(data)
+		if !ok {
+			t.Fatal("data file not encrypted")
+		}
+		tamper(file)
+		data, err = json.Marshal(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		copyDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(copyDir, "data.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		tampered, err := NewStore(copyDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tampered.Unlock("secret"); !errors.Is(err, ErrBadKDFParameters) {
+			t.Errorf("Unlock with n=%d r=%d p=%d: %v, want ErrBadKDFParameters", file.N, file.R, file.P, err)
+		}
+	}
+
+	if err := store.Unlock("secret"); err != nil {
+		t.Errorf("Unlock with the parameters t7t writes: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+func (t *Task) IsBlockedBy(id string) bool {
+	for _, bid := range t.BlockedBy {
+		if bid == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Blockers returns the existing tasks this task waits on.
+func (s *Store) Blockers(task *Task) []*Task {
+	var blockers []*Task
+	for _, id := range task.BlockedBy {
+		if t := s.GetTask(id); t != nil {
+			blockers = append(blockers, t)
+		}
+	}
+	return blockers
+}
+
+// Dependents returns the tasks waiting on this task.
+func (s *Store) Dependents(task *Task) []*Task {
+	var dependents []*Task
+	for _, t := range s.Tasks {
+		if t.IsBlockedBy(task.ID) {
+			dependents = append(dependents, t)
+		}
+	}
+	return dependents
+}
+
+// IsBlocked reports whether any of the task's blockers is still open.
+func (s *Store) IsBlocked(task *Task) bool {
+	for _, b := range s.Blockers(task) {
+		if !b.Completed {
+			return true
+		}
+	}
+	return false
+}
+
+// NewlyUnblocked returns the open dependents of a just completed task that
+// have no open blockers left.
+func (s *Store) NewlyUnblocked(completed *Task) []*Task {
+	if !completed.Completed {
+		return nil
+	}
+	var unblocked []*Task
+	for _, t := range s.Dependents(completed) {
+		if !t.Completed && !s.IsBlocked(t) {
+			unblocked = append(unblocked, t)
+		}
+	}
+	return unblocked
+}
+
+// SetBlockers replaces the tasks blocking the given task. It refuses changes
+// that would make a task (indirectly) wait on itself.
+func (s *Store) SetBlockers(task *Task, blockerIDs []string) error {
+	for _, id := range blockerIDs {
+		if id == task.ID || s.dependsOn(id, task.ID, map[string]bool{}) {
+			return ErrDependencyCycle
+		}
+	}
+	task.BlockedBy = blockerIDs
+	task.touch(time.Now(), "blocked_by")
+	return s.Save()
+}
+
+// dependsOn reports whether the task with the given id waits, directly or
+// through other tasks, on target.
+func (s *Store) dependsOn(id, target string, visited map[string]bool) bool {
+	if visited[id] {
+		return false
+	}
+	visited[id] = true
+
+	task := s.GetTask(id)
+	if task == nil {
+		return false
+	}
+	for _, bid := range task.BlockedBy {
+		if bid == target || s.dependsOn(bid, target, visited) {
+			return true
+		}
+	}
+	return false
+}
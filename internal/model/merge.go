@@ -0,0 +1,396 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Tombstone records that a task or project was deleted.
+type Tombstone struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// MergeAction is what a merge decided for one task, project or field.
+type MergeAction int
+
+const (
+	MergeAdded       MergeAction = iota // only in the other copy, added here
+	MergeKept                           // only here, kept
+	MergeRemoved                        // deleted in the other copy after its last change here
+	MergeSurvived                       // deleted in the other copy but changed here since
+	MergeStayDeleted                    // deleted here after its last change in the other copy
+	MergeRestored                       // deleted here but changed in the other copy since
+	MergeTookField                      // field changed last in the other copy
+	MergeKeptField                      // field changed last here
+)
+
+// MergeDecision describes one decision of a merge. Time is the change or
+// deletion that decided it.
+type MergeDecision struct {
+	Action  MergeAction
+	Project bool
+	Name    string
+	Field   string
+	Time    time.Time
+}
+
+// MergeReport lists every decision of a merge, in task then project order.
+// Items equal on both sides aren't listed, only counted.
+type MergeReport struct {
+	Decisions []MergeDecision
+	Identical int
+}
+
+// Changes counts the decisions that alter this store.
+func (r MergeReport) Changes() int {
+	n := 0
+	for _, d := range r.Decisions {
+		switch d.Action {
+		case MergeAdded, MergeRemoved, MergeRestored, MergeTookField:
+			n++
+		}
+	}
+	return n
+}
+
+// recordMeta is what merging needs to know about a task or project besides
+// its fields.
+type recordMeta struct {
+	id, name         string
+	created, updated time.Time
+	fieldTimes       map[string]time.Time
+}
+
+func taskMeta(t *Task) recordMeta {
+	return recordMeta{t.ID, t.Name, t.CreatedAt, t.UpdatedAt, t.FieldTimes}
+}
+
+func projectMeta(p *Project) recordMeta {
+	return recordMeta{p.ID, p.Name, p.CreatedAt, p.UpdatedAt, p.FieldTimes}
+}
+
+// Merge folds another copy of the data, such as a data file from another
+// machine, into the store. Tasks and projects are matched by ID and merged
+// field by field, each field taking the side that changed it last. A deletion
+// wins over changes made before it. With dryRun the store is left untouched
+// and only the report is returned.
+func (s *Store) Merge(other *Store, dryRun bool) (MergeReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locked {
+		return MergeReport{}, ErrStoreLocked
+	}
+	report, err := s.merge(other, dryRun)
+	if err != nil || dryRun {
+		return report, err
+	}
+	return report, s.write()
+}
+
+// merge is Merge without locking or saving.
+func (s *Store) merge(other *Store, dryRun bool) (MergeReport, error) {
+	var report MergeReport
+	tasks, err := mergeRecords(s.Tasks, other.Tasks, s.Deleted, other.Deleted, false, taskMeta, &report)
+	if err != nil {
+		return report, err
+	}
+	projects, err := mergeRecords(s.Projects, other.Projects, s.Deleted, other.Deleted, true, projectMeta, &report)
+	if err != nil {
+		return report, err
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	s.Tasks, s.Projects = tasks, projects
+	if s.Tasks == nil {
+		s.Tasks = []*Task{}
+	}
+	if s.Projects == nil {
+		s.Projects = []*Project{}
+	}
+	s.Deleted = mergeTombstones(s.Deleted, other.Deleted)
+	s.dropDeletedRefs()
+	return report, nil
+}
+
+func mergeRecords[T any](local, other []*T, localDeleted, otherDeleted []Tombstone, project bool, meta func(*T) recordMeta, report *MergeReport) ([]*T, error) {
+	deletedHere := tombstoneTimes(localDeleted)
+	deletedThere := tombstoneTimes(otherDeleted)
+
+	theirs := make(map[string]*T, len(other))
+	for _, o := range other {
+		theirs[meta(o).id] = o
+	}
+
+	decide := func(action MergeAction, name, field string, at time.Time) {
+		report.Decisions = append(report.Decisions, MergeDecision{action, project, name, field, at})
+	}
+
+	var merged []*T
+	seen := make(map[string]bool, len(local))
+
+	for _, l := range local {
+		lm := meta(l)
+		seen[lm.id] = true
+
+		o, ok := theirs[lm.id]
+		if !ok {
+			deletedAt, buried := deletedThere[lm.id]
+			switch {
+			case !buried:
+				merged = append(merged, l)
+				decide(MergeKept, lm.name, "", lm.updated)
+			case !deletedAt.Before(lm.updated):
+				decide(MergeRemoved, lm.name, "", deletedAt)
+			default:
+				merged = append(merged, l)
+				decide(MergeSurvived, lm.name, "", lm.updated)
+			}
+			continue
+		}
+
+		item, fields, err := mergeFields(l, o, lm, meta(o))
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			report.Identical++
+		}
+		for _, f := range fields {
+			decide(f.Action, lm.name, f.Field, f.Time)
+		}
+		merged = append(merged, item)
+	}
+
+	for _, o := range other {
+		om := meta(o)
+		if seen[om.id] {
+			continue
+		}
+		deletedAt, buried := deletedHere[om.id]
+		switch {
+		case !buried:
+			merged = append(merged, o)
+			decide(MergeAdded, om.name, "", om.updated)
+		case !deletedAt.Before(om.updated):
+			decide(MergeStayDeleted, om.name, "", deletedAt)
+		default:
+			merged = append(merged, o)
+			decide(MergeRestored, om.name, "", om.updated)
+		}
+	}
+
+	return merged, nil
+}
+
+// mergeSkipFields are bookkeeping fields merged separately or never changed.
+var mergeSkipFields = map[string]bool{
+	"id": true, "created_at": true, "updated_at": true, "field_times": true,
+}
+
+// fieldGroup maps JSON fields that change together to the name their time is
+// recorded under.
+func fieldGroup(field string) string {
+	if field == "completed_at" {
+		return "completed"
+	}
+	return field
+}
+
+// fieldTime is when a field last changed on one side. Without a recorded
+// time it's the creation time if the other side recorded one, since then only
+// that side changed it, and otherwise the item's last update.
+func fieldTime(m, peer recordMeta, field string) time.Time {
+	if t, ok := m.fieldTimes[field]; ok {
+		return t
+	}
+	if _, ok := peer.fieldTimes[field]; ok {
+		return m.created
+	}
+	return m.updated
+}
+
+// mergeFields merges two versions of the same item field by field, working on
+// their JSON form so every field is covered. It returns local itself when no
+// field comes from the other side.
+func mergeFields[T any](local, other *T, lm, om recordMeta) (*T, []MergeDecision, error) {
+	localFields, err := jsonFields(local)
+	if err != nil {
+		return nil, nil, err
+	}
+	otherFields, err := jsonFields(other)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	for k := range localFields {
+		keys = append(keys, k)
+	}
+	for k := range otherFields {
+		if _, ok := localFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var decisions []MergeDecision
+	decided := make(map[string]bool)
+	took := false
+	for _, k := range keys {
+		lv, ov := localFields[k], otherFields[k]
+		if mergeSkipFields[k] || bytes.Equal(lv, ov) {
+			continue
+		}
+
+		group := fieldGroup(k)
+		localTime, otherTime := fieldTime(lm, om, group), fieldTime(om, lm, group)
+		action, at := MergeKeptField, localTime
+		if k == "time_entries" {
+			// Time tracked on either side is kept, not just the last
+			// side's list.
+			merged, changed, err := mergeEntriesJSON(lv, ov)
+			if err != nil {
+				return nil, nil, err
+			}
+			if changed {
+				action, at = MergeTookField, otherTime
+				took = true
+				localFields[k] = merged
+			}
+		} else if otherTime.After(localTime) {
+			action, at = MergeTookField, otherTime
+			took = true
+			if ov == nil {
+				delete(localFields, k)
+			} else {
+				localFields[k] = ov
+			}
+		}
+		if !decided[group] {
+			decided[group] = true
+			decisions = append(decisions, MergeDecision{Action: action, Field: group, Time: at})
+		}
+	}
+	if !took {
+		return local, decisions, nil
+	}
+
+	updated := lm.updated
+	if om.updated.After(updated) {
+		updated = om.updated
+	}
+	times := make(map[string]time.Time)
+	for _, m := range []recordMeta{lm, om} {
+		for f, t := range m.fieldTimes {
+			if t.After(times[f]) {
+				times[f] = t
+			}
+		}
+	}
+	for k, v := range map[string]any{"updated_at": updated, "field_times": times} {
+		if localFields[k], err = json.Marshal(v); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	data, err := json.Marshal(localFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	item := new(T)
+	if err := json.Unmarshal(data, item); err != nil {
+		return nil, nil, err
+	}
+	return item, decisions, nil
+}
+
+// mergeEntriesJSON is mergeTimeEntries on the JSON form of the lists.
+func mergeEntriesJSON(local, other json.RawMessage) (json.RawMessage, bool, error) {
+	var lists [2][]TimeEntry
+	for i, data := range []json.RawMessage{local, other} {
+		if len(data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(data, &lists[i]); err != nil {
+			return nil, false, err
+		}
+	}
+	entries, changed := mergeTimeEntries(lists[0], lists[1])
+	if !changed {
+		return local, false, nil
+	}
+	data, err := json.Marshal(entries)
+	return data, true, err
+}
+
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	return fields, json.Unmarshal(data, &fields)
+}
+
+// bury records the deletion of a task or project.
+func (s *Store) bury(id string, at time.Time) {
+	s.Deleted = mergeTombstones(s.Deleted, []Tombstone{{ID: id, DeletedAt: at}})
+}
+
+// mergeTombstones joins two lists of deletions, keeping the latest deletion
+// of each item.
+func mergeTombstones(a, b []Tombstone) []Tombstone {
+	merged := slices.Clone(a)
+	index := make(map[string]int, len(merged))
+	for i, t := range merged {
+		index[t.ID] = i
+	}
+	for _, t := range b {
+		i, ok := index[t.ID]
+		switch {
+		case !ok:
+			index[t.ID] = len(merged)
+			merged = append(merged, t)
+		case t.DeletedAt.After(merged[i].DeletedAt):
+			merged[i] = t
+		}
+	}
+	return merged
+}
+
+func tombstoneTimes(deleted []Tombstone) map[string]time.Time {
+	times := make(map[string]time.Time, len(deleted))
+	for _, t := range deleted {
+		if t.DeletedAt.After(times[t.ID]) {
+			times[t.ID] = t.DeletedAt
+		}
+	}
+	return times
+}
+
+// dropDeletedRefs removes blockers and projects that no longer exist from the
+// tasks, which fields taken from the other side of a merge may still name.
+func (s *Store) dropDeletedRefs() {
+	gone := tombstoneTimes(s.Deleted)
+	for _, t := range s.Tasks {
+		delete(gone, t.ID)
+	}
+	for _, p := range s.Projects {
+		delete(gone, p.ID)
+	}
+	isGone := func(id string) bool {
+		_, ok := gone[id]
+		return ok
+	}
+	for _, t := range s.Tasks {
+		t.BlockedBy = slices.DeleteFunc(t.BlockedBy, isGone)
+		t.ProjectIDs = slices.DeleteFunc(t.ProjectIDs, isGone)
+	}
+}
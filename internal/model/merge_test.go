@@ -0,0 +1,120 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// copies returns two stores holding the same task, as two machines would
+// after a sync.
+func copies(t *testing.T) (*Store, *Store, string) {
+	t.Helper()
+	here, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := NewTask("Write report", "", CategoryToday)
+	if err := here.AddTask(task); err != nil {
+		t.Fatal(err)
+	}
+	there, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := *task
+	if err := there.AddTask(&clone); err != nil {
+		t.Fatal(err)
+	}
+	return here, there, task.ID
+}
+
+// track records a finished time entry on the task.
+func track(t *testing.T, store *Store, id string, start time.Time, d time.Duration) {
+	t.Helper()
+	task := store.GetTask(id)
+	task.StartTimer(start)
+	task.StopTimer(start.Add(d))
+	if err := store.UpdateTask(task); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMergeKeepsTimeTrackedOnBothSides(t *testing.T) {
+	here, there, id := copies(t)
+	start := time.Now().Add(-3 * time.Hour)
+	track(t, here, id, start, time.Hour)
+	track(t, there, id, start.Add(2*time.Hour), 30*time.Minute)
+
+	report, err := here.Merge(there, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := here.GetTask(id)
+	if n := len(task.TimeEntries); n != 2 {
+		t.Fatalf("%d time entries, want both sides' 2", n)
+	}
+	if !task.TimeEntries[0].Start.Equal(start) {
+		t.Errorf("entries not in start order: %v", task.TimeEntries)
+	}
+	if got := task.TrackedTime(time.Now()); got != 90*time.Minute {
+		t.Errorf("tracked %v, want 1h30m", got)
+	}
+	if report.Changes() != 1 {
+		t.Errorf("report %+v, want the other side's entry taken", report.Decisions)
+	}
+
+	// Merging back the other way is a no-op for the merged copy.
+	if _, err := there.Merge(here, false); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(there.GetTask(id).TimeEntries); n != 2 {
+		t.Errorf("other copy has %d entries after merging back, want 2", n)
+	}
+}
+
+func TestMergeTakesStoppedEntry(t *testing.T) {
+	here, there, id := copies(t)
+	start := time.Now().Add(-time.Hour)
+	here.GetTask(id).StartTimer(start)
+	there.GetTask(id).StartTimer(start)
+	there.GetTask(id).StopTimer(start.Add(20 * time.Minute))
+
+	if _, err := here.Merge(there, false); err != nil {
+		t.Fatal(err)
+	}
+	task := here.GetTask(id)
+	if len(task.TimeEntries) != 1 || task.RunningEntry() != nil {
+		t.Errorf("entries %v, want the one entry, stopped", task.TimeEntries)
+	}
+}
+
+func TestMergeVersionKeepsTimeTrackedOnBothSides(t *testing.T) {
+	here, there, id := copies(t)
+	base, err := here.ParseVersion(mustEncode(t, here))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-3 * time.Hour)
+	track(t, here, id, start, time.Hour)
+	track(t, there, id, start.Add(2*time.Hour), 30*time.Minute)
+
+	conflicts, err := here.MergeVersion(base, there)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 {
+		t.Errorf("conflicts %v, want the task changed on both sides", conflicts)
+	}
+	if n := len(here.GetTask(id).TimeEntries); n != 2 {
+		t.Errorf("%d time entries, want both sides' 2", n)
+	}
+}
+
+func mustEncode(t *testing.T, s *Store) []byte {
+	t.Helper()
+	data, err := s.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,51 @@
+package model
+
+const (
+	defaultWorkMinutes       = 25
+	defaultShortBreakMinutes = 5
+	defaultLongBreakMinutes  = 15
+	defaultLongBreakEvery    = 4
+)
+
+type PomodoroSettings struct {
+	WorkMinutes       int  `json:"work_minutes"`
+	ShortBreakMinutes int  `json:"short_break_minutes"`
+	LongBreakMinutes  int  `json:"long_break_minutes"`
+	LongBreakEvery    int  `json:"long_break_every"`
+	Celebrate         bool `json:"celebrate"`
+}
+
+type Settings struct {
+	Pomodoro PomodoroSettings `json:"pomodoro"`
+}
+
+// withDefaults fills unset durations so older data files keep working.
+func (p PomodoroSettings) withDefaults() PomodoroSettings {
+	if p.WorkMinutes <= 0 {
+		p.WorkMinutes = defaultWorkMinutes
+	}
+	if p.ShortBreakMinutes <= 0 {
+		p.ShortBreakMinutes = defaultShortBreakMinutes
+	}
+	if p.LongBreakMinutes <= 0 {
+		p.LongBreakMinutes = defaultLongBreakMinutes
+	}
+	if p.LongBreakEvery <= 0 {
+		p.LongBreakEvery = defaultLongBreakEvery
+	}
+	return p
+}
+
+func (s *Store) PomodoroSettings() PomodoroSettings {
+	return s.Settings.Pomodoro.withDefaults()
+}
+
+func (s *Store) SetPomodoroSettings(settings PomodoroSettings) error {
+	s.Settings.Pomodoro = settings.withDefaults()
+	return s.Save()
+}
+
+func (s *Store) RecordPomodoro(task *Task) error {
+	task.AddPomodoro()
+	return s.Save()
+}
@@ -13,6 +13,9 @@ type Project struct {
 	Completed bool      `json:"completed"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// FieldTimes records when each field last changed, see Task.FieldTimes.
+	FieldTimes map[string]time.Time `json:"field_times,omitempty"`
 }
 
 func NewProject(name string) *Project {
@@ -28,12 +31,17 @@ func NewProject(name string) *Project {
 
 func (p *Project) ToggleComplete() {
 	p.Completed = !p.Completed
-	p.UpdatedAt = time.Now()
+	p.touch(time.Now(), "completed")
 }
 
 func (p *Project) Update(name string) {
 	p.Name = name
-	p.UpdatedAt = time.Now()
+	p.touch(time.Now(), "name")
+}
+
+func (p *Project) touch(now time.Time, fields ...string) {
+	p.UpdatedAt = now
+	touchFields(&p.FieldTimes, now, fields)
 }
 
 func (p Project) FilterValue() string {
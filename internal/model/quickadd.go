@@ -0,0 +1,115 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// QuickAdd is a task typed on one line with inline syntax: `!week` picks the
+// category, `+infra` adds a project (with `_` for spaces), `due:fri` sets the
+// due date and `@work` contexts stay in the name, where they're highlighted.
+// Tokens that don't parse are kept in the name as typed, and so are `+` words
+// that don't start with a letter, such as `+55` or `+1`.
+type QuickAdd struct {
+	Name     string
+	Category Category // empty when the line doesn't give one
+	Projects []string
+	Due      *time.Time
+}
+
+// Weekday names accepted in due:, in every language, by the day they stand
+// for.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday, "dom": time.Sunday, "domingo": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday, "seg": time.Monday, "segunda": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday, "ter": time.Tuesday, "terca": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday, "qua": time.Wednesday, "quarta": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday, "qui": time.Thursday, "quinta": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday, "sex": time.Friday, "sexta": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday, "sab": time.Saturday, "sabado": time.Saturday,
+}
+
+// ParseQuickAdd reads a line of quick-add syntax. Due dates relative to a day
+// of the week are taken from now.
+func ParseQuickAdd(line string, now time.Time) QuickAdd {
+	var q QuickAdd
+	var words []string
+	for _, word := range strings.Fields(line) {
+		switch {
+		case len(word) > 1 && word[0] == '!':
+			if category, ok := ParseCategory(word[1:]); ok {
+				q.Category = category
+				continue
+			}
+		case len(word) > 1 && word[0] == '+':
+			if first, _ := utf8.DecodeRuneInString(word[1:]); unicode.IsLetter(first) {
+				q.Projects = append(q.Projects, word[1:])
+				continue
+			}
+		case strings.HasPrefix(strings.ToLower(word), "due:"):
+			if due, ok := parseDue(word[len("due:"):], now); ok {
+				q.Due = &due
+				continue
+			}
+		}
+		words = append(words, word)
+	}
+	q.Name = strings.Join(words, " ")
+	return q
+}
+
+// parseDue reads a date as YYYY-MM-DD, today, tomorrow or a day of the week,
+// which means the next one from now, now included.
+func parseDue(value string, now time.Time) (time.Time, bool) {
+	now = now.Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	value = strings.ToLower(value)
+	switch value {
+	case "today", "hoje":
+		return today, true
+	case "tomorrow", "amanha":
+		return today.AddDate(0, 0, 1), true
+	}
+	if day, ok := weekdays[value]; ok {
+		return today.AddDate(0, 0, (int(day)-int(today.Weekday())+7)%7), true
+	}
+	due, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	return due, err == nil
+}
+
+// Task builds the task, in the category the line gives or else in fallback,
+// with its projects found or created through the batch.
+func (q QuickAdd) Task(store *Store, batch *Batch, fallback Category) *Task {
+	category := q.Category
+	if category == "" {
+		category = fallback
+	}
+	task := NewTask(q.Name, "", category)
+	task.Due = q.Due
+	for _, name := range q.ProjectNames(store) {
+		project := batch.Project(store, name)
+		if !task.HasProject(project.ID) {
+			task.ProjectIDs = append(task.ProjectIDs, project.ID)
+		}
+	}
+	return task
+}
+
+// ProjectNames returns the names the line's projects go by: an existing
+// project's own name, or the name typed with `_` turned into spaces.
+func (q QuickAdd) ProjectNames(store *Store) []string {
+	names := make([]string, len(q.Projects))
+	for i, name := range q.Projects {
+		spaced := strings.ReplaceAll(name, "_", " ")
+		names[i] = spaced
+		for _, candidate := range []string{name, spaced} {
+			if p := store.ProjectByName(candidate); p != nil {
+				names[i] = p.Name
+				break
+			}
+		}
+	}
+	return names
+}
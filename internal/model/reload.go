@@ -0,0 +1,185 @@
+package model
+
+import (
+	"os"
+	"time"
+)
+
+// diskState remembers what the data file looked like the last time this store
+// read or wrote it, so changes made by other processes can be detected and
+// merged instead of overwritten.
+type diskState struct {
+	modTime  time.Time
+	size     int64
+	tasks    map[string]time.Time
+	projects map[string]time.Time
+	settings Settings
+}
+
+// ReloadResult describes what a reload picked up from disk. Conflicts holds
+// the names of tasks and projects changed both here and on disk; the newer
+// version was kept for each of them.
+type ReloadResult struct {
+	Changed   bool
+	Conflicts []string
+}
+
+// snapshot records the current file stamp and item versions as the common
+// base for the next merge.
+func (s *Store) snapshot() {
+	s.disk.tasks = make(map[string]time.Time, len(s.Tasks))
+	for _, t := range s.Tasks {
+		s.disk.tasks[t.ID] = t.UpdatedAt
+	}
+	s.disk.projects = make(map[string]time.Time, len(s.Projects))
+	for _, p := range s.Projects {
+		s.disk.projects[p.ID] = p.UpdatedAt
+	}
+	s.disk.settings = s.Settings
+	if info, err := os.Stat(s.path); err == nil {
+		s.disk.modTime = info.ModTime()
+		s.disk.size = info.Size()
+	}
+}
+
+func (s *Store) changedOnDisk() bool {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false
+	}
+	return !info.ModTime().Equal(s.disk.modTime) || info.Size() != s.disk.size
+}
+
+// Reload merges changes another process wrote to the data file since this
+// store last read or wrote it. Changes merged while saving are reported too.
+func (s *Store) Reload() (ReloadResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := ReloadResult{Changed: s.merged, Conflicts: s.conflicts}
+	s.merged, s.conflicts = false, nil
+
+	if s.locked || !s.changedOnDisk() {
+		return result, nil
+	}
+
+	conflicts, err := s.mergeFromDisk()
+	if err != nil {
+		return result, err
+	}
+	result.Changed = true
+	result.Conflicts = append(result.Conflicts, conflicts...)
+
+	// Local edits that survived the merge aren't on disk yet.
+	if s.dirty || len(conflicts) > 0 {
+		return result, s.write()
+	}
+	s.snapshot()
+	return result, nil
+}
+
+// mergeFromDisk folds the file contents into the store, three-way against the
+// last snapshot. Items are updated in place so pointers held elsewhere stay
+// valid. The settings on disk are taken unless they also changed here.
+func (s *Store) mergeFromDisk() ([]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	var disk Store
+	if err := s.decode(data, &disk); err != nil {
+		return nil, err
+	}
+
+	var conflicts, c []string
+	s.Tasks, c = mergeItems(s.Tasks, disk.Tasks, s.disk.tasks,
+		func(t *Task) (string, string, time.Time) { return t.ID, t.Name, t.UpdatedAt }, keepTimeEntries)
+	conflicts = append(conflicts, c...)
+	s.Projects, c = mergeItems(s.Projects, disk.Projects, s.disk.projects,
+		func(p *Project) (string, string, time.Time) { return p.ID, p.Name, p.UpdatedAt }, nil)
+	conflicts = append(conflicts, c...)
+	s.Deleted = mergeTombstones(s.Deleted, disk.Deleted)
+
+	if s.Settings == s.disk.settings {
+		s.Settings = disk.Settings
+	}
+	return conflicts, nil
+}
+
+// mergeItems merges the items read from disk into the local ones. An item
+// changed on only one side takes that side's version, deletions win over
+// untouched items, and when both sides changed the newer one wins and the
+// item is reported as a conflict. keep, if set, then copies onto the winner
+// what it must not lose from the other version.
+func mergeItems[T any](local, disk []*T, base map[string]time.Time, info func(*T) (id, name string, updated time.Time), keep func(winner, loser *T)) ([]*T, []string) {
+	onDisk := make(map[string]*T, len(disk))
+	for _, d := range disk {
+		id, _, _ := info(d)
+		onDisk[id] = d
+	}
+
+	var merged []*T
+	var conflicts []string
+	seen := make(map[string]bool, len(local))
+
+	for _, l := range local {
+		id, name, localUpdated := info(l)
+		seen[id] = true
+		baseUpdated, inBase := base[id]
+		localChanged := !inBase || !localUpdated.Equal(baseUpdated)
+
+		d, ok := onDisk[id]
+		if !ok {
+			switch {
+			case !inBase:
+				merged = append(merged, l)
+			case localChanged:
+				merged = append(merged, l)
+				conflicts = append(conflicts, name)
+			}
+			continue
+		}
+
+		_, _, diskUpdated := info(d)
+		diskChanged := !inBase || !diskUpdated.Equal(baseUpdated)
+		switch {
+		case !diskChanged:
+		case !localChanged:
+			*l = *d
+		default:
+			loser := d
+			if diskUpdated.After(localUpdated) {
+				lost := *l
+				*l, loser = *d, &lost
+			}
+			if keep != nil {
+				keep(l, loser)
+			}
+			conflicts = append(conflicts, name)
+		}
+		merged = append(merged, l)
+	}
+
+	for _, d := range disk {
+		id, name, diskUpdated := info(d)
+		if seen[id] {
+			continue
+		}
+		baseUpdated, inBase := base[id]
+		switch {
+		case !inBase:
+			merged = append(merged, d)
+		case diskUpdated.After(baseUpdated):
+			merged = append(merged, d)
+			conflicts = append(conflicts, name)
+		}
+	}
+
+	return merged, conflicts
+}
+
+// keepTimeEntries keeps the time tracked on both versions of a task changed
+// on two sides.
+func keepTimeEntries(winner, loser *Task) {
+	winner.TimeEntries, _ = mergeTimeEntries(winner.TimeEntries, loser.TimeEntries)
+}
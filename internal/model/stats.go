@@ -0,0 +1,119 @@
+package model
+
+import "time"
+
+const (
+	statsDays  = 14
+	statsWeeks = 8
+)
+
+type DayCount struct {
+	Day   time.Time
+	Count int
+}
+
+type CategoryAge struct {
+	Category   Category
+	Open       int
+	AverageAge time.Duration
+}
+
+type ProjectProgress struct {
+	Project *Project
+	Total   int
+	Done    int
+}
+
+type Stats struct {
+	CompletedPerDay   []DayCount
+	CompletedPerWeek  []DayCount
+	CompletedToday    int
+	CompletedWeek     int
+	CompletedLastWeek int
+	OpenByCategory    []CategoryAge
+	Projects          []ProjectProgress
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the Monday that starts the week containing t.
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// ComputeStats summarizes completion throughput, open task age and project
+// progress as seen at the given moment.
+func (s *Store) ComputeStats(now time.Time) Stats {
+	today := startOfDay(now)
+	thisWeek := startOfWeek(now)
+	lastWeek := thisWeek.AddDate(0, 0, -7)
+
+	stats := Stats{
+		CompletedPerDay:  make([]DayCount, statsDays),
+		CompletedPerWeek: make([]DayCount, statsWeeks),
+	}
+	for i := range stats.CompletedPerDay {
+		stats.CompletedPerDay[i].Day = today.AddDate(0, 0, i-statsDays+1)
+	}
+	for i := range stats.CompletedPerWeek {
+		stats.CompletedPerWeek[i].Day = thisWeek.AddDate(0, 0, 7*(i-statsWeeks+1))
+	}
+
+	ages := make(map[Category]time.Duration)
+	open := make(map[Category]int)
+
+	for _, t := range s.Tasks {
+		if !t.Completed {
+			open[t.Category]++
+			ages[t.Category] += now.Sub(t.CreatedAt)
+			continue
+		}
+
+		done := t.CompletionTime()
+		if !done.Before(today) {
+			stats.CompletedToday++
+		}
+		if !done.Before(thisWeek) {
+			stats.CompletedWeek++
+		} else if !done.Before(lastWeek) {
+			stats.CompletedLastWeek++
+		}
+
+		days := int(today.Sub(startOfDay(done)).Hours()/24 + 0.5)
+		if days >= 0 && days < statsDays {
+			stats.CompletedPerDay[statsDays-1-days].Count++
+		}
+		weeks := int(thisWeek.Sub(startOfWeek(done)).Hours()/(24*7) + 0.5)
+		if weeks >= 0 && weeks < statsWeeks {
+			stats.CompletedPerWeek[statsWeeks-1-weeks].Count++
+		}
+	}
+
+	for _, c := range Categories {
+		age := CategoryAge{Category: c, Open: open[c]}
+		if open[c] > 0 {
+			age.AverageAge = ages[c] / time.Duration(open[c])
+		}
+		stats.OpenByCategory = append(stats.OpenByCategory, age)
+	}
+
+	for _, p := range s.Projects {
+		progress := ProjectProgress{Project: p}
+		for _, t := range s.Tasks {
+			if t.HasProject(p.ID) {
+				progress.Total++
+				if t.Completed {
+					progress.Done++
+				}
+			}
+		}
+		stats.Projects = append(stats.Projects, progress)
+	}
+
+	return stats
+}
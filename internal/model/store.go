@@ -1,26 +1,49 @@
 package model
 
 import (
-	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 )
 
 type Store struct {
 	Tasks    []*Task    `json:"tasks"`
 	Projects []*Project `json:"projects"`
-	path     string
-	mu       sync.RWMutex
-}
+	Settings Settings   `json:"settings"`
 
-func NewStore() (*Store, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
+	// Deleted remembers removed tasks and projects so a merge with an
+	// older copy of the data doesn't bring them back.
+	Deleted []Tombstone `json:"deleted,omitempty"`
+
+	path  string
+	dirty bool
+	disk  diskState
+
+	// crypt is set for encrypted stores once unlocked. A locked store has
+	// not been loaded yet and refuses to save.
+	crypt  *encryption
+	locked bool
+
+	// merged and conflicts report merges done while saving until the
+	// next Reload picks them up.
+	merged    bool
+	conflicts []string
+
+	// hookErr is what the save hooks reported on the last save.
+	hookErr error
+
+	// saveVia, when set, saves the store in place of writing the file.
+	saveVia func(store *Store, settingsChanged bool) error
+
+	mu sync.RWMutex
+}
 
-	dataDir := filepath.Join(homeDir, ".t7t")
+// NewStore opens the data.json inside dataDir, creating the directory if
+// needed.
+func NewStore(dataDir string) (*Store, error) {
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, err
 	}
@@ -31,13 +54,20 @@ func NewStore() (*Store, error) {
 		path:     filepath.Join(dataDir, "data.json"),
 	}
 
-	if err := store.Load(); err != nil && !os.IsNotExist(err) {
+	if err := store.Load(); errors.Is(err, ErrPassphraseRequired) {
+		store.locked = true
+	} else if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
 
 	return store, nil
 }
 
+// Dir returns the directory holding the data file.
+func (s *Store) Dir() string {
+	return filepath.Dir(s.path)
+}
+
 func (s *Store) Load() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -47,19 +77,159 @@ func (s *Store) Load() error {
 		return err
 	}
 
-	return json.Unmarshal(data, s)
+	// Start from empty lists so reloading doesn't decode into the
+	// previous tasks.
+	s.Tasks, s.Projects, s.Deleted = nil, nil, nil
+	if err := s.decode(data, s); err != nil {
+		return err
+	}
+	s.snapshot()
+	return nil
 }
 
+// Save writes the store to disk. If another process changed the file since it
+// was last read, those changes are merged in first rather than overwritten.
 func (s *Store) Save() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	data, err := json.MarshalIndent(s, "", "  ")
+	if s.saveVia != nil && !s.locked {
+		err := s.saveVia(s, s.Settings != s.disk.settings)
+		if err == nil {
+			return s.adoptDisk()
+		}
+		// Write the file here only when whoever saves for us is gone;
+		// otherwise it's writing the file too.
+		if !errors.Is(err, ErrSaverGone) {
+			s.dirty = true
+			return err
+		}
+	}
+	if s.changedOnDisk() {
+		if conflicts, err := s.mergeFromDisk(); err == nil {
+			s.merged = true
+			s.conflicts = append(s.conflicts, conflicts...)
+		}
+	}
+	return s.write()
+}
+
+// ErrSaverGone is what a function given to SaveVia returns when it can't
+// reach whoever saves for the store, so the store writes the file itself.
+var ErrSaverGone = errors.New("can't reach the process saving the data")
+
+// SaveVia hands the store's saves to save, such as a daemon that owns the
+// data file and merges this copy into its own, instead of writing the file
+// here. It's told whether the settings changed since the file was last read,
+// so unchanged ones don't overwrite newer settings. Nil goes back to writing
+// the file.
+func (s *Store) SaveVia(save func(store *Store, settingsChanged bool) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saveVia = save
+}
+
+// adoptDisk merges in the file written for the store by SaveVia, which holds
+// this copy's changes along with the ones made elsewhere.
+func (s *Store) adoptDisk() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	var disk Store
+	if err := s.decode(data, &disk); err != nil {
+		return err
+	}
+	report, err := s.merge(&disk, false)
+	if err != nil {
+		return err
+	}
+	if report.Changes() > 0 || s.Settings != disk.Settings {
+		s.merged = true
+	}
+	s.Settings = disk.Settings
+	s.dirty = false
+	s.snapshot()
+	return nil
+}
+
+func (s *Store) write() error {
+	if s.locked {
+		return ErrStoreLocked
+	}
+	data, err := s.encode()
+	if err == nil {
+		err = writeFileAtomic(s.path, data, 0644)
+	}
+	s.dirty = err != nil
+	if err == nil {
+		s.snapshot()
+		s.backup(data, time.Now(), false)
+		var hookErrs []error
+		for _, hook := range saveHooks {
+			if err := hook(s, s.crypt != nil); err != nil {
+				hookErrs = append(hookErrs, err)
+			}
+		}
+		s.hookErr = errors.Join(hookErrs...)
+	}
+	return err
+}
+
+// saveHooks run after every successful save, with the store locked, so they
+// must not call methods that lock it. They're told whether the store is
+// encrypted for that reason.
+var saveHooks []func(store *Store, encrypted bool) error
+
+// OnSave registers a function to run after every save of any store, to keep
+// files derived from the data, such as calendar exports, up to date.
+// A hook that fails doesn't fail the save; its error is kept for HookError.
+func OnSave(hook func(store *Store, encrypted bool) error) {
+	saveHooks = append(saveHooks, hook)
+}
+
+// HookError returns what the save hooks reported on the last save, if any,
+// and forgets it, so each failure is reported once.
+func (s *Store) HookError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.hookErr
+	s.hookErr = nil
+	return err
+}
+
+// Dirty reports whether the last save failed, leaving changes that only
+// exist in memory.
+func (s *Store) Dirty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dirty
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so a failed write never leaves a truncated data file behind.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(tmp.Name())
 
-	return os.WriteFile(s.path, data, 0644)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
 // Task operations
@@ -77,9 +247,11 @@ func (s *Store) DeleteTask(id string) error {
 	for i, t := range s.Tasks {
 		if t.ID == id {
 			s.Tasks = append(s.Tasks[:i], s.Tasks[i+1:]...)
+			s.bury(id, time.Now())
 			break
 		}
 	}
+	s.removeBlocker(id)
 	return s.Save()
 }
 
@@ -90,10 +262,43 @@ func (s *Store) DeleteCompletedTasks(category Category) error {
 			remaining = append(remaining, t)
 		}
 	}
+	now := time.Now()
+	for _, t := range s.Tasks {
+		if t.Completed && t.Category == category {
+			s.removeBlocker(t.ID)
+			s.bury(t.ID, now)
+		}
+	}
 	s.Tasks = remaining
 	return s.Save()
 }
 
+// removeBlocker drops a deleted task from the blockers of the remaining tasks.
+func (s *Store) removeBlocker(id string) {
+	for _, t := range s.Tasks {
+		var blockedBy []string
+		for _, bid := range t.BlockedBy {
+			if bid != id {
+				blockedBy = append(blockedBy, bid)
+			}
+		}
+		t.BlockedBy = blockedBy
+	}
+}
+
+// AddBatch adds the projects and tasks of a batch and applies its updates,
+// saving once.
+func (s *Store) AddBatch(b *Batch) error {
+	s.Projects = append(s.Projects, b.Projects...)
+	s.Tasks = append(s.Tasks, b.Tasks...)
+	for _, update := range b.Updates {
+		if task := s.GetTask(update.ID); task != nil {
+			apply(task, update)
+		}
+	}
+	return s.Save()
+}
+
 func (s *Store) GetTasksByCategory(category Category) []*Task {
 	var tasks []*Task
 	for _, t := range s.Tasks {
@@ -128,6 +333,7 @@ func (s *Store) DeleteProject(id string) error {
 	for i, p := range s.Projects {
 		if p.ID == id {
 			s.Projects = append(s.Projects[:i], s.Projects[i+1:]...)
+			s.bury(id, time.Now())
 			break
 		}
 	}
@@ -153,6 +359,16 @@ func (s *Store) GetProject(id string) *Project {
 	return nil
 }
 
+// ProjectByName finds a project by name, ignoring case.
+func (s *Store) ProjectByName(name string) *Project {
+	for _, p := range s.Projects {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	return nil
+}
+
 func (s *Store) GetProjects() []*Project {
 	return s.Projects
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"t7t/internal/i18n"
 	"time"
 
@@ -16,12 +17,45 @@ const (
 	CategoryGeneral   Category = "general"
 )
 
+// Categories lists every category in tab order.
+var Categories = []Category{CategoryToday, CategoryWeek, CategoryNotUrgent, CategoryGeneral}
+
+// Valid reports whether c is one of the known categories.
+func (c Category) Valid() bool {
+	for _, known := range Categories {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
 func (c Category) String() string {
 	return CategoryString(c)
 }
 
 func CategoryString(c Category) string {
-	m := i18n.Get()
+	return categoryLabel(i18n.Get(), c)
+}
+
+// ParseCategory reads a category given by key or by its label in any
+// language, ignoring case, as found in imported files.
+func ParseCategory(name string) (Category, bool) {
+	name = strings.TrimSpace(name)
+	for _, c := range Categories {
+		if strings.EqualFold(name, string(c)) {
+			return c, true
+		}
+		for _, lang := range i18n.AvailableLanguages() {
+			if strings.EqualFold(name, categoryLabel(i18n.For(lang), c)) {
+				return c, true
+			}
+		}
+	}
+	return "", false
+}
+
+func categoryLabel(m *i18n.Messages, c Category) string {
 	switch c {
 	case CategoryToday:
 		return m.CategoryToday
@@ -37,14 +71,24 @@ func CategoryString(c Category) string {
 }
 
 type Task struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Category    Category  `json:"category"`
-	Completed   bool      `json:"completed"`
-	ProjectIDs  []string  `json:"project_ids"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Category    Category    `json:"category"`
+	Completed   bool        `json:"completed"`
+	ProjectIDs  []string    `json:"project_ids"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+	TimeEntries []TimeEntry `json:"time_entries,omitempty"`
+	Pomodoros   int         `json:"pomodoros,omitempty"`
+	BlockedBy   []string    `json:"blocked_by,omitempty"`
+	Due         *time.Time  `json:"due,omitempty"`
+	Priority    string      `json:"priority,omitempty"`
+
+	// FieldTimes records when each field last changed, keyed by its JSON
+	// name, so merges can keep the latest change of every field.
+	FieldTimes map[string]time.Time `json:"field_times,omitempty"`
 }
 
 func NewTask(name, description string, category Category) *Task {
@@ -62,24 +106,83 @@ func NewTask(name, description string, category Category) *Task {
 }
 
 func (t *Task) ToggleComplete() {
+	now := time.Now()
 	t.Completed = !t.Completed
-	t.UpdatedAt = time.Now()
+	t.touch(now, "completed")
+	if t.Completed {
+		t.CompletedAt = &now
+	} else {
+		t.CompletedAt = nil
+	}
+}
+
+// CompletionTime returns when the task was completed. Tasks completed before
+// completion times were recorded fall back to their last update.
+func (t *Task) CompletionTime() time.Time {
+	if t.CompletedAt != nil {
+		return *t.CompletedAt
+	}
+	return t.UpdatedAt
+}
+
+func (t *Task) AddPomodoro() {
+	t.Pomodoros++
+	t.touch(time.Now(), "pomodoros")
 }
 
 func (t *Task) SetCategory(category Category) {
 	t.Category = category
-	t.UpdatedAt = time.Now()
+	t.touch(time.Now(), "category")
 }
 
 func (t *Task) Update(name, description string) {
+	var changed []string
+	if name != t.Name {
+		changed = append(changed, "name")
+	}
+	if description != t.Description {
+		changed = append(changed, "description")
+	}
 	t.Name = name
 	t.Description = description
-	t.UpdatedAt = time.Now()
+	t.touch(time.Now(), changed...)
 }
 
 func (t *Task) SetProjects(projectIDs []string) {
 	t.ProjectIDs = projectIDs
-	t.UpdatedAt = time.Now()
+	t.touch(time.Now(), "project_ids")
+}
+
+// SetDue sets the due date, or clears it when due is nil.
+func (t *Task) SetDue(due *time.Time) {
+	t.Due = due
+	t.touch(time.Now(), "due")
+}
+
+// SetPriority sets the priority, a letter from A (highest) to Z, or clears it
+// when empty.
+func (t *Task) SetPriority(priority string) {
+	t.Priority = priority
+	t.touch(time.Now(), "priority")
+}
+
+// touch marks the task as updated at now, recording the time for each of the
+// given fields.
+func (t *Task) touch(now time.Time, fields ...string) {
+	t.UpdatedAt = now
+	touchFields(&t.FieldTimes, now, fields)
+}
+
+func touchFields(times *map[string]time.Time, now time.Time, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	if *times == nil {
+		*times = make(map[string]time.Time)
+	}
+	for _, f := range fields {
+		(*times)[f] = now
+	}
 }
 
 func (t *Task) HasProject(projectID string) bool {
@@ -0,0 +1,225 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type TimeEntry struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+func (e TimeEntry) Running() bool {
+	return e.End == nil
+}
+
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	if e.End != nil {
+		return e.End.Sub(e.Start)
+	}
+	return now.Sub(e.Start)
+}
+
+func (t *Task) RunningEntry() *TimeEntry {
+	for i := range t.TimeEntries {
+		if t.TimeEntries[i].Running() {
+			return &t.TimeEntries[i]
+		}
+	}
+	return nil
+}
+
+func (t *Task) StartTimer(now time.Time) {
+	if t.RunningEntry() != nil {
+		return
+	}
+	t.TimeEntries = append(t.TimeEntries, TimeEntry{Start: now})
+	t.touch(now, "time_entries")
+}
+
+// StopTimer closes the running time entry and returns how long it ran.
+func (t *Task) StopTimer(now time.Time) time.Duration {
+	entry := t.RunningEntry()
+	if entry == nil {
+		return 0
+	}
+	entry.End = &now
+	t.touch(now, "time_entries")
+	return entry.Duration(now)
+}
+
+// mergeTimeEntries joins the time entries of two copies of a task. Entries
+// are only ever added, so both sides' are kept; one started at the same time
+// on both sides is the same entry, taken stopped if either side stopped it.
+// It reports whether other added anything to local.
+func mergeTimeEntries(local, other []TimeEntry) ([]TimeEntry, bool) {
+	merged := slices.Clone(local)
+	index := make(map[int64]int, len(merged))
+	for i, e := range merged {
+		index[e.Start.UnixNano()] = i
+	}
+	changed := false
+	for _, e := range other {
+		i, ok := index[e.Start.UnixNano()]
+		switch {
+		case !ok:
+			index[e.Start.UnixNano()] = len(merged)
+			merged = append(merged, e)
+			changed = true
+		case merged[i].Running() && !e.Running():
+			merged[i] = e
+			changed = true
+		}
+	}
+	if changed {
+		sort.SliceStable(merged, func(i, j int) bool { return merged[i].Start.Before(merged[j].Start) })
+	}
+	return merged, changed
+}
+
+func (t *Task) TrackedTime(now time.Time) time.Duration {
+	var total time.Duration
+	for _, e := range t.TimeEntries {
+		total += e.Duration(now)
+	}
+	return total
+}
+
+// Timer operations
+
+func (s *Store) RunningTimer() *Task {
+	for _, t := range s.Tasks {
+		if t.RunningEntry() != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// StartTimer starts tracking time on the task. Only one timer runs at a time,
+// so any other running timer is stopped first.
+func (s *Store) StartTimer(task *Task) error {
+	now := time.Now()
+	if running := s.RunningTimer(); running != nil && running != task {
+		running.StopTimer(now)
+	}
+	task.StartTimer(now)
+	return s.Save()
+}
+
+func (s *Store) StopTimer() (*Task, time.Duration, error) {
+	task := s.RunningTimer()
+	if task == nil {
+		return nil, 0, nil
+	}
+	elapsed := task.StopTimer(time.Now())
+	return task, elapsed, s.Save()
+}
+
+type TimeTotal struct {
+	Label    string
+	Duration time.Duration
+}
+
+// TimeReport holds the tracked time in a period. ByTask is labeled with task
+// IDs, since names aren't unique; look the tasks up to show them.
+type TimeReport struct {
+	ByTask    []TimeTotal
+	ByProject []TimeTotal
+	ByDay     []TimeTotal
+	Total     time.Duration
+}
+
+// TimeReport sums the time entries started on or after since. Tasks without
+// projects are reported under an empty project label.
+func (s *Store) TimeReport(since, now time.Time) TimeReport {
+	byTask := make(map[string]time.Duration)
+	byProject := make(map[string]time.Duration)
+	byDay := make(map[string]time.Duration)
+	var report TimeReport
+
+	for _, t := range s.Tasks {
+		for _, e := range t.TimeEntries {
+			if e.Start.Before(since) {
+				continue
+			}
+			d := e.Duration(now)
+			report.Total += d
+			byTask[t.ID] += d
+			byDay[e.Start.Format("2006-01-02")] += d
+
+			names := s.GetProjectNames(t.ProjectIDs)
+			if len(names) == 0 {
+				byProject[""] += d
+			}
+			for _, name := range names {
+				byProject[name] += d
+			}
+		}
+	}
+
+	report.ByTask = sortedTotals(byTask, false)
+	report.ByProject = sortedTotals(byProject, false)
+	report.ByDay = sortedTotals(byDay, true)
+	return report
+}
+
+func sortedTotals(totals map[string]time.Duration, byLabel bool) []TimeTotal {
+	var result []TimeTotal
+	for label, d := range totals {
+		result = append(result, TimeTotal{Label: label, Duration: d})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if byLabel || result[i].Duration == result[j].Duration {
+			return result[i].Label < result[j].Label
+		}
+		return result[i].Duration > result[j].Duration
+	})
+	return result
+}
+
+// FormatDuration renders a duration as hours and minutes, e.g. "1h05m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// FormatClock renders a duration as a running clock, e.g. "01:05:09".
+func FormatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// FindTasks returns the tasks whose ID starts with ref or, when no ID
+// matches, whose name contains ref (case-insensitive).
+func (s *Store) FindTasks(ref string) []*Task {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil
+	}
+
+	var matches []*Task
+	for _, t := range s.Tasks {
+		if strings.HasPrefix(t.ID, ref) {
+			matches = append(matches, t)
+		}
+	}
+	if len(matches) > 0 {
+		return matches
+	}
+
+	lower := strings.ToLower(ref)
+	for _, t := range s.Tasks {
+		if strings.EqualFold(t.Name, ref) {
+			return []*Task{t}
+		}
+		if strings.Contains(strings.ToLower(t.Name), lower) {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,91 @@
+package model
+
+import "time"
+
+// ChangeSummary lists the tasks that differ between two versions of the data.
+type ChangeSummary struct {
+	Added     []string
+	Completed []string
+	Updated   []string
+	Deleted   []string
+}
+
+func (c ChangeSummary) Empty() bool {
+	return len(c.Added)+len(c.Completed)+len(c.Updated)+len(c.Deleted) == 0
+}
+
+// ParseVersion decodes another version of the data file, such as one read
+// from version control, with this store's key.
+func (s *Store) ParseVersion(data []byte) (*Store, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	version := &Store{}
+	if err := s.decode(data, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// MergeVersion folds theirs into the store, three-way against base, the last
+// version both sides had in common (empty when there is none). A task or
+// project changed on both sides keeps the newer version and is reported.
+func (s *Store) MergeVersion(base, theirs *Store) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	baseTasks := make(map[string]time.Time)
+	baseProjects := make(map[string]time.Time)
+	if base != nil {
+		for _, t := range base.Tasks {
+			baseTasks[t.ID] = t.UpdatedAt
+		}
+		for _, p := range base.Projects {
+			baseProjects[p.ID] = p.UpdatedAt
+		}
+	}
+
+	var conflicts, c []string
+	s.Tasks, c = mergeItems(s.Tasks, theirs.Tasks, baseTasks,
+		func(t *Task) (string, string, time.Time) { return t.ID, t.Name, t.UpdatedAt }, keepTimeEntries)
+	conflicts = append(conflicts, c...)
+	s.Projects, c = mergeItems(s.Projects, theirs.Projects, baseProjects,
+		func(p *Project) (string, string, time.Time) { return p.ID, p.Name, p.UpdatedAt }, nil)
+	conflicts = append(conflicts, c...)
+	s.Deleted = mergeTombstones(s.Deleted, theirs.Deleted)
+
+	return conflicts, s.write()
+}
+
+// DescribeChanges summarizes how the tasks changed from before to after.
+// A nil before counts every task as added.
+func DescribeChanges(before, after *Store) ChangeSummary {
+	old := make(map[string]*Task)
+	if before != nil {
+		for _, t := range before.Tasks {
+			old[t.ID] = t
+		}
+	}
+
+	var summary ChangeSummary
+	for _, t := range after.Tasks {
+		prev, ok := old[t.ID]
+		delete(old, t.ID)
+		switch {
+		case !ok:
+			summary.Added = append(summary.Added, t.Name)
+		case t.Completed && !prev.Completed:
+			summary.Completed = append(summary.Completed, t.Name)
+		case !t.UpdatedAt.Equal(prev.UpdatedAt):
+			summary.Updated = append(summary.Updated, t.Name)
+		}
+	}
+	if before != nil {
+		for _, t := range before.Tasks {
+			if _, ok := old[t.ID]; ok {
+				summary.Deleted = append(summary.Deleted, t.Name)
+			}
+		}
+	}
+	return summary
+}
@@ -0,0 +1,271 @@
+// Package org converts tasks to and from Org-mode outlines: a top-level
+// heading per category with the tasks below it as TODO and DONE headlines,
+// tagged with their projects. Each task keeps its t7t ID in an :ID: property,
+// so importing an edited export updates the tasks instead of duplicating
+// them.
+package org
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"t7t/internal/model"
+)
+
+const (
+	dateFormat     = "2006-01-02 Mon"
+	dateTimeFormat = "2006-01-02 Mon 15:04"
+
+	// bodyIndent lines up drawers and descriptions under the headline text,
+	// and keeps description lines starting with * from being read as
+	// headlines.
+	bodyIndent = "   "
+)
+
+var (
+	headlineRegex  = regexp.MustCompile(`^(\*+)\s+(.*?)\s*$`)
+	taskRegex      = regexp.MustCompile(`^(TODO|DONE)(?:\s+\[#([A-Z])\])?(?:\s+(.*?))?(?:\s+(:[\w@#%:]+:))?$`)
+	propertyRegex  = regexp.MustCompile(`^:([\w-]+):\s*(.*)$`)
+	timestampRegex = regexp.MustCompile(`(CLOSED|DEADLINE|SCHEDULED):\s*[\[<](\d{4}-\d{2}-\d{2})(?:\s+[^\]>\s\d]+)?(?:\s+(\d{1,2}:\d{2}))?[^\]>]*[\]>]`)
+	tagCharsRegex  = regexp.MustCompile(`[^\w@#%]+`)
+)
+
+// Export writes a top-level heading per category, leaving out the empty ones.
+func Export(store *model.Store, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, category := range model.Categories {
+		tasks := store.GetTasksByCategory(category)
+		if len(tasks) == 0 {
+			continue
+		}
+		bw.WriteString("* " + model.CategoryString(category) + "\n")
+		for _, task := range tasks {
+			writeTask(bw, task, store.GetProjectNames(task.ProjectIDs))
+		}
+	}
+	return bw.Flush()
+}
+
+func writeTask(w *bufio.Writer, task *model.Task, projects []string) {
+	keyword := "TODO"
+	if task.Completed {
+		keyword = "DONE"
+	}
+	headline := "** " + keyword
+	if task.Priority != "" {
+		headline += " [#" + task.Priority + "]"
+	}
+	headline += " " + task.Name
+	if len(projects) > 0 {
+		tags := make([]string, len(projects))
+		for i, name := range projects {
+			tags[i] = Tag(name)
+		}
+		headline += " :" + strings.Join(tags, ":") + ":"
+	}
+	w.WriteString(headline + "\n")
+
+	var planning []string
+	if task.Completed {
+		planning = append(planning, "CLOSED: ["+task.CompletionTime().Local().Format(dateTimeFormat)+"]")
+	}
+	if task.Due != nil {
+		planning = append(planning, "DEADLINE: <"+formatDay(*task.Due)+">")
+	}
+	if len(planning) > 0 {
+		w.WriteString(bodyIndent + strings.Join(planning, " ") + "\n")
+	}
+
+	w.WriteString(bodyIndent + ":PROPERTIES:\n")
+	w.WriteString(bodyIndent + ":ID:       " + task.ID + "\n")
+	w.WriteString(bodyIndent + ":END:\n")
+
+	if description := strings.TrimRight(task.Description, " \n"); description != "" {
+		for _, line := range strings.Split(description, "\n") {
+			if strings.TrimSpace(line) == "" {
+				w.WriteString("\n")
+				continue
+			}
+			w.WriteString(bodyIndent + line + "\n")
+		}
+	}
+}
+
+// Tag turns a project name into an Org tag, which can't hold spaces or most
+// punctuation.
+func Tag(name string) string {
+	return strings.Trim(tagCharsRegex.ReplaceAllString(name, "_"), "_")
+}
+
+func formatDay(t time.Time) string {
+	local := t.Local()
+	if local.Hour() == 0 && local.Minute() == 0 {
+		return local.Format(dateFormat)
+	}
+	return local.Format(dateTimeFormat)
+}
+
+// Parse reads TODO and DONE headlines into a batch. A heading naming a
+// category puts the tasks below it in that category. Tasks whose :ID: is
+// already in the store become updates to those tasks; the others are new.
+func Parse(store *model.Store, r io.Reader) (*model.Batch, error) {
+	p := &parser{store: store, batch: &model.Batch{}, category: model.CategoryGeneral, seen: make(map[string]bool)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		p.line(strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	p.flush()
+	return p.batch, nil
+}
+
+type parser struct {
+	store    *model.Store
+	batch    *model.Batch
+	category model.Category
+	seen     map[string]bool
+
+	// The task being read, with its project tags and body.
+	task     *model.Task
+	tags     []string
+	body     []string
+	inDrawer bool
+	seenBody bool
+}
+
+func (p *parser) line(line string) {
+	if m := headlineRegex.FindStringSubmatch(line); m != nil {
+		p.flush()
+		p.headline(len(m[1]), m[2])
+		return
+	}
+	if p.task == nil {
+		return
+	}
+
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case p.inDrawer:
+		if strings.EqualFold(trimmed, ":END:") {
+			p.inDrawer = false
+		} else if m := propertyRegex.FindStringSubmatch(trimmed); m != nil && strings.EqualFold(m[1], "ID") && m[2] != "" {
+			p.task.ID = m[2]
+		}
+	case !p.seenBody && strings.EqualFold(trimmed, ":PROPERTIES:"):
+		p.inDrawer = true
+	case !p.seenBody && timestampRegex.MatchString(trimmed):
+		p.planning(trimmed)
+	default:
+		if trimmed != "" {
+			p.seenBody = true
+		}
+		p.body = append(p.body, strings.TrimPrefix(line, bodyIndent))
+	}
+}
+
+func (p *parser) headline(level int, text string) {
+	m := taskRegex.FindStringSubmatch(text)
+	if m == nil {
+		// Only top-level headings name categories, so an outline's own
+		// sections don't move tasks around.
+		if level == 1 {
+			if category, ok := model.ParseCategory(text); ok {
+				p.category = category
+			}
+		}
+		return
+	}
+	name := strings.TrimSpace(m[3])
+	if name == "" {
+		return
+	}
+
+	p.task = model.NewTask(name, "", p.category)
+	p.task.Priority = m[2]
+	if m[1] == "DONE" {
+		now := time.Now()
+		p.task.Completed = true
+		p.task.CompletedAt = &now
+	}
+	if m[4] != "" {
+		p.tags = strings.Split(strings.Trim(m[4], ":"), ":")
+	}
+}
+
+func (p *parser) planning(line string) {
+	for _, m := range timestampRegex.FindAllStringSubmatch(line, -1) {
+		layout, value := "2006-01-02", m[2]
+		if m[3] != "" {
+			layout, value = "2006-01-02 15:04", m[2]+" "+m[3]
+		}
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err != nil {
+			continue
+		}
+		switch m[1] {
+		case "CLOSED":
+			if p.task.Completed {
+				p.task.CompletedAt = &t
+			}
+		case "DEADLINE":
+			p.task.Due = &t
+		}
+	}
+}
+
+// flush finishes the task being read, if any.
+func (p *parser) flush() {
+	task := p.task
+	if task == nil {
+		return
+	}
+	task.Description = strings.Trim(strings.Join(p.body, "\n"), "\n")
+	for _, tag := range p.tags {
+		project := p.project(tag)
+		if !task.HasProject(project.ID) {
+			task.ProjectIDs = append(task.ProjectIDs, project.ID)
+		}
+	}
+
+	switch existing := p.store.GetTask(task.ID); {
+	case p.seen[task.ID]:
+		// A copy of a task earlier in the file.
+	case existing != nil:
+		// Keep whitespace the export trimmed from the description.
+		if normalize(existing.Description) == task.Description {
+			task.Description = existing.Description
+		}
+		p.batch.Update(p.store, task)
+	default:
+		p.batch.Tasks = append(p.batch.Tasks, task)
+	}
+	p.seen[task.ID] = true
+	p.task, p.tags, p.body = nil, nil, nil
+	p.inDrawer, p.seenBody = false, false
+}
+
+// normalize trims a description the way it reads back from an export.
+func normalize(description string) string {
+	lines := strings.Split(description, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// project finds the project a tag stands for, whose name may have had spaces
+// and punctuation replaced on export.
+func (p *parser) project(tag string) *model.Project {
+	for _, project := range p.store.Projects {
+		if strings.EqualFold(Tag(project.Name), tag) {
+			return project
+		}
+	}
+	return p.batch.Project(p.store, strings.ReplaceAll(tag, "_", " "))
+}
@@ -0,0 +1,208 @@
+// Package report builds a self-contained HTML status page from the tasks, for
+// sharing with people who don't use t7t: styles are inlined and nothing is
+// loaded from elsewhere.
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+// recentDays is how far back the recently completed section goes.
+const recentDays = 7
+
+var contextRegex = regexp.MustCompile(`@[\w-]+`)
+
+// Raw HTML in descriptions is left out, so a report can't carry scripts, and
+// images become links, so opening it doesn't fetch anything.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithASTTransformers(util.Prioritized(imagesAsLinks{}, 100))),
+)
+
+// imagesAsLinks turns each image into a link to it, labeled with its alt text
+// or, without one, its address. An image that is already inside a link, such
+// as a badge, is left as just its label.
+type imagesAsLinks struct{}
+
+func (imagesAsLinks) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var images []*ast.Image
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if img, ok := n.(*ast.Image); ok && entering {
+			images = append(images, img)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, img := range images {
+		if !img.HasChildren() {
+			img.AppendChild(img, ast.NewString(img.Destination))
+		}
+		parent := img.Parent()
+		if insideLink(img) {
+			for c := img.FirstChild(); c != nil; c = img.FirstChild() {
+				parent.InsertBefore(parent, img, c)
+			}
+			parent.RemoveChild(parent, img)
+			continue
+		}
+		link := ast.NewLink()
+		link.Destination = img.Destination
+		link.Title = img.Title
+		for c := img.FirstChild(); c != nil; c = img.FirstChild() {
+			link.AppendChild(link, c)
+		}
+		parent.ReplaceChild(parent, img, link)
+	}
+}
+
+func insideLink(n ast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if _, ok := p.(*ast.Link); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type page struct {
+	Lang       string
+	Messages   *i18n.Messages
+	Generated  string
+	Summary    []string
+	RecentDays int
+	Categories []categorySection
+	Projects   []projectBar
+	Recent     []taskItem
+}
+
+type categorySection struct {
+	Name  string
+	Tasks []taskItem
+}
+
+type projectBar struct {
+	Name      string
+	Done      int
+	Total     int
+	Percent   int
+	Completed bool
+}
+
+type taskItem struct {
+	Name        template.HTML
+	Projects    []string
+	Due         string
+	Overdue     bool
+	Completed   string
+	Description template.HTML
+}
+
+// HTML writes the report as seen at now: the open tasks of each category,
+// project progress and the tasks completed in the last days.
+func HTML(store *model.Store, w io.Writer, now time.Time) error {
+	m := i18n.Get()
+	stats := store.ComputeStats(now)
+
+	p := page{
+		Lang:       string(i18n.GetLanguage()),
+		Messages:   m,
+		Generated:  now.Format(m.ReportDateTimeFormat),
+		RecentDays: recentDays,
+		Summary: []string{
+			fmt.Sprintf(m.StatsToday, stats.CompletedToday),
+			fmt.Sprintf(m.StatsThisWeek, stats.CompletedWeek),
+			fmt.Sprintf(m.StatsLastWeek, stats.CompletedLastWeek),
+		},
+	}
+
+	for _, category := range model.Categories {
+		section := categorySection{Name: model.CategoryString(category)}
+		for _, task := range store.GetTasksByCategory(category) {
+			if !task.Completed {
+				section.Tasks = append(section.Tasks, item(store, task, now))
+			}
+		}
+		p.Categories = append(p.Categories, section)
+	}
+
+	for _, progress := range stats.Projects {
+		if progress.Total == 0 {
+			continue
+		}
+		p.Projects = append(p.Projects, projectBar{
+			Name:      progress.Project.Name,
+			Done:      progress.Done,
+			Total:     progress.Total,
+			Percent:   progress.Done * 100 / progress.Total,
+			Completed: progress.Project.Completed,
+		})
+	}
+
+	since := now.AddDate(0, 0, -recentDays)
+	var recent []*model.Task
+	for _, task := range store.Tasks {
+		if task.Completed && task.CompletionTime().After(since) {
+			recent = append(recent, task)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].CompletionTime().After(recent[j].CompletionTime())
+	})
+	for _, task := range recent {
+		p.Recent = append(p.Recent, item(store, task, now))
+	}
+
+	return pageTemplate.Execute(w, p)
+}
+
+func item(store *model.Store, task *model.Task, now time.Time) taskItem {
+	m := i18n.Get()
+	it := taskItem{
+		Name:        highlightContexts(task.Name),
+		Projects:    store.GetProjectNames(task.ProjectIDs),
+		Description: render(task.Description),
+	}
+	if task.Due != nil {
+		it.Due = fmt.Sprintf(m.ReportDue, task.Due.Local().Format(m.ReportDateFormat))
+		it.Overdue = !task.Completed && task.Due.Before(now)
+	}
+	if task.Completed {
+		it.Completed = task.CompletionTime().Local().Format(m.ReportDateTimeFormat)
+	}
+	return it
+}
+
+// highlightContexts escapes a task name, marking its @context tags the way
+// the TUI colors them.
+func highlightContexts(name string) template.HTML {
+	escaped := html.EscapeString(name)
+	return template.HTML(contextRegex.ReplaceAllString(escaped, `<span class="context">$0</span>`))
+}
+
+func render(description string) template.HTML {
+	if description == "" {
+		return ""
+	}
+	var b bytes.Buffer
+	if err := markdown.Convert([]byte(description), &b); err != nil {
+		return template.HTML("<pre>" + html.EscapeString(description) + "</pre>")
+	}
+	return template.HTML(b.String())
+}
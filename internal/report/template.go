@@ -0,0 +1,52 @@
+package report
+
+import "html/template"
+
+// pageTemplate keeps its styles inline so the page can be mailed or dropped
+// anywhere as a single file.
+var pageTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Messages.ReportTitle}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; max-width: 52rem; margin: 2rem auto; padding: 0 1rem; color: #1f2328; line-height: 1.5; }
+h1 { margin-bottom: 0; }
+h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .3rem; margin-top: 2rem; }
+.generated, .empty, .meta { color: #656d76; }
+.summary { display: flex; gap: 1.5rem; flex-wrap: wrap; margin: 1rem 0; }
+.summary span { background: #f6f8fa; border-radius: 6px; padding: .3rem .8rem; }
+ul.tasks { list-style: none; padding-left: 0; }
+ul.tasks > li { padding: .5rem 0; border-bottom: 1px solid #eaeef2; }
+.name { font-weight: 600; }
+.context { color: #8250df; }
+.project { display: inline-block; background: #ddf4ff; color: #0969da; border-radius: 1rem; padding: 0 .6rem; margin-left: .4rem; font-size: .85em; }
+.due { margin-left: .4rem; font-size: .85em; color: #656d76; }
+.overdue { color: #cf222e; font-weight: 600; }
+.description { margin: .3rem 0 0 1rem; font-size: .95em; }
+.description pre { background: #f6f8fa; padding: .6rem; border-radius: 6px; overflow-x: auto; }
+.bar { display: flex; align-items: center; gap: .8rem; margin: .4rem 0; }
+.bar .label { width: 14rem; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+.bar .track { flex: 1; background: #eaeef2; border-radius: 4px; height: .8rem; }
+.bar .fill { background: #2da44e; border-radius: 4px; height: 100%; }
+.bar .count { width: 6rem; text-align: right; color: #656d76; }
+</style>
+</head>
+<body>
+<h1>{{.Messages.ReportTitle}}</h1>
+<p class="generated">{{printf .Messages.ReportGenerated .Generated}}</p>
+<div class="summary">{{range .Summary}}<span>{{.}}</span>{{end}}</div>
+{{range .Categories}}
+<h2>{{.Name}}</h2>
+{{if .Tasks}}<ul class="tasks">{{range .Tasks}}{{template "task" .}}{{end}}</ul>{{else}}<p class="empty">{{$.Messages.ReportNoOpenTasks}}</p>{{end}}
+{{end}}
+<h2>{{.Messages.StatsProjects}}</h2>
+{{if .Projects}}{{range .Projects}}<div class="bar"><span class="label">{{.Name}}{{if .Completed}} ✓{{end}}</span><div class="track"><div class="fill" style="width: {{.Percent}}%"></div></div><span class="count">{{.Done}}/{{.Total}}</span></div>
+{{end}}{{else}}<p class="empty">{{.Messages.StatsNoProjects}}</p>{{end}}
+<h2>{{printf .Messages.ReportRecentlyCompleted .RecentDays}}</h2>
+{{if .Recent}}<ul class="tasks">{{range .Recent}}{{template "task" .}}{{end}}</ul>{{else}}<p class="empty">{{.Messages.ReportNoRecent}}</p>{{end}}
+</body>
+</html>
+{{define "task"}}<li><span class="name">{{.Name}}</span>{{range .Projects}}<span class="project">{{.}}</span>{{end}}{{if .Due}}<span class="due{{if .Overdue}} overdue{{end}}">{{.Due}}</span>{{end}}{{if .Completed}} <span class="meta">· {{.Completed}}</span>{{end}}{{if .Description}}<div class="description">{{.Description}}</div>{{end}}</li>
+{{end}}`))
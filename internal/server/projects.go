@@ -0,0 +1,94 @@
+package server
+
+import (
+	"net/http"
+
+	"t7t/internal/model"
+)
+
+type projectInput struct {
+	Name *string `json:"name"`
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	projects := s.store.GetProjects()
+	if projects == nil {
+		projects = []*model.Project{}
+	}
+	writeJSON(w, r, http.StatusOK, projects)
+}
+
+// project looks up the project named in the path, answering 404 if there is
+// none.
+func (s *Server) project(w http.ResponseWriter, r *http.Request) *model.Project {
+	project := s.store.GetProject(r.PathValue("id"))
+	if project == nil {
+		writeError(w, http.StatusNotFound, errNotFound)
+	}
+	return project
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	if project := s.project(w, r); project != nil {
+		writeJSON(w, r, http.StatusOK, project)
+	}
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	var in projectInput
+	if !readJSON(w, r, &in) {
+		return
+	}
+	if in.Name == nil || *in.Name == "" {
+		writeError(w, http.StatusBadRequest, errName)
+		return
+	}
+
+	project := model.NewProject(*in.Name)
+	w.Header().Set("Location", "/api/projects/"+project.ID)
+	saved(w, r, http.StatusCreated, project, s.store.AddProject(project))
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request) {
+	project := s.project(w, r)
+	if project == nil || !precondition(w, r, project) {
+		return
+	}
+	var in projectInput
+	if !readJSON(w, r, &in) {
+		return
+	}
+	if in.Name != nil {
+		if *in.Name == "" {
+			writeError(w, http.StatusBadRequest, errName)
+			return
+		}
+		project.Update(*in.Name)
+	}
+	saved(w, r, http.StatusOK, project, s.store.UpdateProject(project))
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	project := s.project(w, r)
+	if project == nil || !precondition(w, r, project) {
+		return
+	}
+	if err := s.store.DeleteProject(project.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) completeProject(completed bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		project := s.project(w, r)
+		if project == nil || !precondition(w, r, project) {
+			return
+		}
+		if project.Completed != completed {
+			project.ToggleComplete()
+		}
+		saved(w, r, http.StatusOK, project, s.store.UpdateProject(project))
+	}
+}
@@ -0,0 +1,203 @@
+// Package server exposes a store through a local HTTP/JSON API so editor
+// plugins, scripts and dashboards can use it without reading the data file.
+//
+// Every request needs the API token as a bearer token. Responses carry an
+// ETag; sending it back in If-Match makes a change fail with 412 if the item
+// changed in the meantime, and in If-None-Match makes a GET answer 304 while
+// nothing changed.
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"t7t/internal/model"
+)
+
+const tokenFile = "api-token"
+
+var (
+	errUnauthorized = errors.New("missing or invalid token")
+	errNotFound     = errors.New("not found")
+	errPrecondition = errors.New("changed since the given ETag")
+	errName         = errors.New("name is required")
+	errCategory     = errors.New("unknown category")
+	errProject      = errors.New("unknown project")
+	errBlocker      = errors.New("unknown blocking task")
+)
+
+// Server serves the API for one store. Requests are handled one at a time.
+type Server struct {
+	store *model.Store
+	token string
+	mu    sync.Mutex
+}
+
+func New(store *model.Store, token string) *Server {
+	return &Server{store: store, token: token}
+}
+
+// LoadToken returns the API token kept in dir, creating a random one the
+// first time. The token file is readable by its owner only.
+func LoadToken(dir string) (string, error) {
+	path := filepath.Join(dir, tokenFile)
+	if data, err := os.ReadFile(path); err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	return token, os.WriteFile(path, []byte(token+"\n"), 0600)
+}
+
+// TokenPath returns where LoadToken keeps the token for dir.
+func TokenPath(dir string) string {
+	return filepath.Join(dir, tokenFile)
+}
+
+// Handler returns the API routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/tasks", s.listTasks)
+	mux.HandleFunc("POST /api/tasks", s.createTask)
+	mux.HandleFunc("GET /api/tasks/{id}", s.getTask)
+	mux.HandleFunc("PATCH /api/tasks/{id}", s.updateTask)
+	mux.HandleFunc("DELETE /api/tasks/{id}", s.deleteTask)
+	mux.HandleFunc("POST /api/tasks/{id}/complete", s.completeTask(true))
+	mux.HandleFunc("POST /api/tasks/{id}/reopen", s.completeTask(false))
+	mux.HandleFunc("POST /api/tasks/{id}/move", s.moveTask)
+
+	mux.HandleFunc("GET /api/projects", s.listProjects)
+	mux.HandleFunc("POST /api/projects", s.createProject)
+	mux.HandleFunc("GET /api/projects/{id}", s.getProject)
+	mux.HandleFunc("PATCH /api/projects/{id}", s.updateProject)
+	mux.HandleFunc("DELETE /api/projects/{id}", s.deleteProject)
+	mux.HandleFunc("POST /api/projects/{id}/complete", s.completeProject(true))
+	mux.HandleFunc("POST /api/projects/{id}/reopen", s.completeProject(false))
+
+	return s.guard(mux)
+}
+
+// guard checks the token, serializes requests and picks up changes other
+// processes wrote to the data file before handling each request.
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="t7t"`)
+			writeError(w, http.StatusUnauthorized, errUnauthorized)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, err := s.store.Reload(); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorBody{err.Error()})
+}
+
+// writeJSON sends v with an ETag computed from its encoding, or 304 when the
+// request already has that version.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	tag := etagOf(data)
+	w.Header().Set("ETag", tag)
+	if r.Method == http.MethodGet && matchesETag(r.Header.Get("If-None-Match"), tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+func etagOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// etag is the ETag a GET of v would return.
+func etag(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return etagOf(data)
+}
+
+// matchesETag reports whether an If-Match or If-None-Match header names tag.
+func matchesETag(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// precondition enforces If-Match against the current version of v. Without
+// the header, changes always apply.
+func precondition(w http.ResponseWriter, r *http.Request, v any) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" || matchesETag(header, etag(v)) {
+		return true
+	}
+	writeError(w, http.StatusPreconditionFailed, errPrecondition)
+	return false
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// saved answers with v once the store has been written, or with the save
+// error.
+func saved(w http.ResponseWriter, r *http.Request, status int, v any, err error) {
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, r, status, v)
+}
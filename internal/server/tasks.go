@@ -0,0 +1,218 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"t7t/internal/model"
+)
+
+type taskInput struct {
+	Name        *string         `json:"name"`
+	Description *string         `json:"description"`
+	Category    *model.Category `json:"category"`
+	ProjectIDs  *[]string       `json:"project_ids"`
+	BlockedBy   *[]string       `json:"blocked_by"`
+}
+
+type moveInput struct {
+	Category model.Category `json:"category"`
+}
+
+// validate checks the references in a task input against the store.
+func (s *Server) validate(in taskInput) error {
+	if in.Name != nil && *in.Name == "" {
+		return errName
+	}
+	if in.Category != nil && !in.Category.Valid() {
+		return errCategory
+	}
+	if in.ProjectIDs != nil {
+		for _, id := range *in.ProjectIDs {
+			if s.store.GetProject(id) == nil {
+				return errProject
+			}
+		}
+	}
+	if in.BlockedBy != nil {
+		for _, id := range *in.BlockedBy {
+			if s.store.GetTask(id) == nil {
+				return errBlocker
+			}
+		}
+	}
+	return nil
+}
+
+// listTasks returns the tasks, optionally filtered by the category, project
+// and completed query parameters.
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	category := model.Category(query.Get("category"))
+	project := query.Get("project")
+	completed, filterCompleted := query.Get("completed"), query.Has("completed")
+
+	tasks := []*model.Task{}
+	for _, t := range s.store.Tasks {
+		if category != "" && t.Category != category {
+			continue
+		}
+		if project != "" && !t.HasProject(project) {
+			continue
+		}
+		if filterCompleted {
+			want, err := strconv.ParseBool(completed)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			if t.Completed != want {
+				continue
+			}
+		}
+		tasks = append(tasks, t)
+	}
+	writeJSON(w, r, http.StatusOK, tasks)
+}
+
+// task looks up the task named in the path, answering 404 if there is none.
+func (s *Server) task(w http.ResponseWriter, r *http.Request) *model.Task {
+	task := s.store.GetTask(r.PathValue("id"))
+	if task == nil {
+		writeError(w, http.StatusNotFound, errNotFound)
+	}
+	return task
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	if task := s.task(w, r); task != nil {
+		writeJSON(w, r, http.StatusOK, task)
+	}
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var in taskInput
+	if !readJSON(w, r, &in) {
+		return
+	}
+	if in.Name == nil {
+		writeError(w, http.StatusBadRequest, errName)
+		return
+	}
+	if err := s.validate(in); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var description string
+	if in.Description != nil {
+		description = *in.Description
+	}
+	category := model.CategoryToday
+	if in.Category != nil {
+		category = *in.Category
+	}
+	task := model.NewTask(*in.Name, description, category)
+	if in.ProjectIDs != nil {
+		task.ProjectIDs = *in.ProjectIDs
+	}
+	if in.BlockedBy != nil {
+		task.BlockedBy = *in.BlockedBy
+	}
+
+	w.Header().Set("Location", "/api/tasks/"+task.ID)
+	saved(w, r, http.StatusCreated, task, s.store.AddTask(task))
+}
+
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request) {
+	task := s.task(w, r)
+	if task == nil || !precondition(w, r, task) {
+		return
+	}
+	var in taskInput
+	if !readJSON(w, r, &in) {
+		return
+	}
+	if err := s.validate(in); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Blockers go first: a cycle rejects the whole request before anything
+	// else changed.
+	if in.BlockedBy != nil {
+		err := s.store.SetBlockers(task, *in.BlockedBy)
+		if errors.Is(err, model.ErrDependencyCycle) {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if in.Name != nil || in.Description != nil {
+		name, description := task.Name, task.Description
+		if in.Name != nil {
+			name = *in.Name
+		}
+		if in.Description != nil {
+			description = *in.Description
+		}
+		task.Update(name, description)
+	}
+	if in.Category != nil && *in.Category != task.Category {
+		task.SetCategory(*in.Category)
+	}
+	if in.ProjectIDs != nil {
+		task.SetProjects(*in.ProjectIDs)
+	}
+	saved(w, r, http.StatusOK, task, s.store.UpdateTask(task))
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	task := s.task(w, r)
+	if task == nil || !precondition(w, r, task) {
+		return
+	}
+	if err := s.store.DeleteTask(task.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// completeTask marks a task as completed or open again. Doing so when it
+// already is leaves it untouched.
+func (s *Server) completeTask(completed bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		task := s.task(w, r)
+		if task == nil || !precondition(w, r, task) {
+			return
+		}
+		if task.Completed != completed {
+			task.ToggleComplete()
+		}
+		saved(w, r, http.StatusOK, task, s.store.UpdateTask(task))
+	}
+}
+
+func (s *Server) moveTask(w http.ResponseWriter, r *http.Request) {
+	task := s.task(w, r)
+	if task == nil || !precondition(w, r, task) {
+		return
+	}
+	var in moveInput
+	if !readJSON(w, r, &in) {
+		return
+	}
+	if !in.Category.Valid() {
+		writeError(w, http.StatusBadRequest, errCategory)
+		return
+	}
+	if in.Category != task.Category {
+		task.SetCategory(in.Category)
+	}
+	saved(w, r, http.StatusOK, task, s.store.UpdateTask(task))
+}
@@ -0,0 +1,344 @@
+// Package taskcsv reads and writes tasks as CSV, one row per task, for
+// spreadsheets. Imports can map the file's own column names and category
+// names to t7t's.
+package taskcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"t7t/internal/model"
+)
+
+// Columns are the fields a row can hold, in export order.
+var Columns = []string{"id", "name", "description", "category", "completed", "projects", "created", "updated"}
+
+// projectSeparator joins project names in the projects column. Names are
+// more likely to hold commas than semicolons, unless semicolons already
+// separate the fields.
+func projectSeparator(comma rune) string {
+	if comma == ';' {
+		return ","
+	}
+	return ";"
+}
+
+var (
+	ErrNoNameColumn    = errors.New("no column holds the task names")
+	ErrUnknownColumn   = errors.New("unknown column")
+	ErrEmptyName       = errors.New("empty task name")
+	ErrUnknownCategory = errors.New("unknown category")
+	ErrBadBool         = errors.New("not a yes/no value")
+	ErrBadTime         = errors.New("not a date")
+	ErrDuplicateID     = errors.New("another row has the same ID")
+)
+
+// RowError is a problem with one row of an import.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// RowErrors collects every invalid row, so they can all be fixed at once.
+type RowErrors []RowError
+
+func (e RowErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Options adjusts an import to a file made elsewhere.
+type Options struct {
+	// Comma separates the fields, ',' when zero.
+	Comma rune
+
+	// Mapping names the file's column for a field, for fields whose column
+	// isn't named after them.
+	Mapping map[string]string
+
+	// Aliases map category names used in the file to categories, on top of
+	// the keys and labels t7t knows in every language.
+	Aliases map[string]model.Category
+}
+
+// Export writes every task as a row under a header of Columns.
+func Export(store *model.Store, w io.Writer, comma rune) error {
+	cw := csv.NewWriter(w)
+	if comma != 0 {
+		cw.Comma = comma
+	}
+	separator := projectSeparator(cw.Comma) + " "
+	cw.Write(Columns)
+	for _, task := range store.Tasks {
+		cw.Write([]string{
+			task.ID,
+			task.Name,
+			task.Description,
+			string(task.Category),
+			strconv.FormatBool(task.Completed),
+			strings.Join(store.GetProjectNames(task.ProjectIDs), separator),
+			task.CreatedAt.Local().Format(time.RFC3339),
+			task.UpdatedAt.Local().Format(time.RFC3339),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Parse reads the tasks in a CSV file with a header row. Nothing is returned
+// unless every row is valid; otherwise the error is a RowErrors listing each
+// problem by line.
+func Parse(store *model.Store, r io.Reader, opts Options) (*model.Batch, error) {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return &model.Batch{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	index, err := columnIndex(header, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &model.Batch{}
+	ids := make(map[string]bool)
+	var rowErrors RowErrors
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, RowError{Line: parseErr.StartLine, Err: parseErr.Err})
+				continue
+			}
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if blank(record) {
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		task, err := parseRow(store, batch, field, cr.Comma, opts.Aliases)
+		if err == nil && ids[task.ID] {
+			err = fmt.Errorf("%w: %s", ErrDuplicateID, task.ID)
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Err: err})
+			continue
+		}
+		ids[task.ID] = true
+		if existing := store.GetTask(task.ID); existing != nil {
+			keepMissing(task, existing, index)
+			batch.Update(store, task)
+		} else {
+			batch.Tasks = append(batch.Tasks, task)
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, rowErrors
+	}
+	return batch, nil
+}
+
+// columnIndex finds the column of each field: the one the mapping names, or
+// else the one named after the field, ignoring case.
+func columnIndex(header []string, mapping map[string]string) (map[string]int, error) {
+	for field := range mapping {
+		if !isColumn(field) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, field)
+		}
+	}
+	// Spreadsheet apps often start UTF-8 files with a byte order mark.
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	index := make(map[string]int)
+	for _, field := range Columns {
+		name := field
+		if mapped, ok := mapping[field]; ok {
+			name = mapped
+		}
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				index[field] = i
+				break
+			}
+		}
+		if _, ok := index[field]; !ok && mapping[field] != "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, mapping[field])
+		}
+	}
+	if _, ok := index["name"]; !ok {
+		return nil, ErrNoNameColumn
+	}
+	return index, nil
+}
+
+func isColumn(field string) bool {
+	for _, c := range Columns {
+		if c == field {
+			return true
+		}
+	}
+	return false
+}
+
+func blank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseRow(store *model.Store, batch *model.Batch, field func(string) string, comma rune, aliases map[string]model.Category) (*model.Task, error) {
+	name := field("name")
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+
+	category := model.CategoryGeneral
+	if value := field("category"); value != "" {
+		var ok bool
+		if category, ok = parseCategory(value, aliases); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCategory, value)
+		}
+	}
+
+	task := model.NewTask(name, field("description"), category)
+
+	// Spreadsheets often number their rows, which makes poor task IDs, so
+	// the id column only counts when it names a task here or is a UUID.
+	if id := field("id"); id != "" {
+		if _, err := uuid.Parse(id); err == nil || store.GetTask(id) != nil {
+			task.ID = id
+		}
+	}
+
+	if value := field("completed"); value != "" {
+		completed, ok := parseBool(value)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrBadBool, value)
+		}
+		task.Completed = completed
+	}
+
+	for _, column := range []string{"created", "updated"} {
+		value := field(column)
+		if value == "" {
+			continue
+		}
+		t, ok := parseTime(value)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrBadTime, value)
+		}
+		if column == "created" {
+			task.CreatedAt = t
+		} else {
+			task.UpdatedAt = t
+		}
+	}
+	if task.Completed {
+		completedAt := task.UpdatedAt
+		task.CompletedAt = &completedAt
+	}
+
+	for _, name := range strings.Split(field("projects"), projectSeparator(comma)) {
+		if name = strings.TrimSpace(name); name != "" {
+			project := batch.Project(store, name)
+			if !task.HasProject(project.ID) {
+				task.ProjectIDs = append(task.ProjectIDs, project.ID)
+			}
+		}
+	}
+	return task, nil
+}
+
+// keepMissing copies onto a row updating a task the fields the file has no
+// column for, so the update leaves them alone.
+func keepMissing(task, existing *model.Task, index map[string]int) {
+	has := func(column string) bool {
+		_, ok := index[column]
+		return ok
+	}
+	if !has("description") {
+		task.Description = existing.Description
+	}
+	if !has("category") {
+		task.Category = existing.Category
+	}
+	if !has("completed") {
+		task.Completed, task.CompletedAt = existing.Completed, existing.CompletedAt
+	}
+	if !has("projects") {
+		task.ProjectIDs = existing.ProjectIDs
+	}
+	task.Due, task.Priority = existing.Due, existing.Priority
+}
+
+func parseCategory(value string, aliases map[string]model.Category) (model.Category, bool) {
+	for alias, category := range aliases {
+		if strings.EqualFold(alias, value) {
+			return category, true
+		}
+	}
+	return model.ParseCategory(value)
+}
+
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "y", "x", "1", "sim", "s", "done":
+		return true, true
+	case "false", "no", "n", "0", "nao", "não", "":
+		return false, true
+	}
+	return false, false
+}
+
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
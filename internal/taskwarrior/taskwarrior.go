@@ -0,0 +1,241 @@
+// Package taskwarrior converts tasks to and from the JSON that Taskwarrior's
+// `task export` writes and `task import` reads.
+//
+// Taskwarrior has no lists like t7t's, so imported tasks are sorted into
+// categories by their urgency. Exports keep the category, and any projects
+// after the first, in t7t_ attributes that Taskwarrior stores along with the
+// task, so they survive a trip through it.
+package taskwarrior
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"t7t/internal/model"
+)
+
+const timeFormat = "20060102T150405Z"
+
+// Urgency bands: tasks at least this urgent go to the category.
+const (
+	todayUrgency     = 8.0 // due today or overdue, or high priority and due soon
+	weekUrgency      = 5.0 // due within about a week, or high priority
+	notUrgentUrgency = 2.0 // some priority, project or tags, but no rush
+)
+
+// Taskwarrior's priorities, mapped to the letters t7t keeps from todo.txt.
+var priorities = map[string]string{"H": "A", "M": "B", "L": "C"}
+
+var contextRegex = regexp.MustCompile(`@([\w-]+)`)
+
+type twTask struct {
+	UUID        string         `json:"uuid"`
+	Description string         `json:"description"`
+	Status      string         `json:"status"`
+	Entry       string         `json:"entry,omitempty"`
+	Modified    string         `json:"modified,omitempty"`
+	End         string         `json:"end,omitempty"`
+	Due         string         `json:"due,omitempty"`
+	Project     string         `json:"project,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Priority    string         `json:"priority,omitempty"`
+	Annotations []twAnnotation `json:"annotations,omitempty"`
+	Urgency     float64        `json:"urgency,omitempty"`
+
+	Category string   `json:"t7t_category,omitempty"`
+	Projects []string `json:"t7t_projects,omitempty"`
+}
+
+type twAnnotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+// Parse reads the output of `task export` into a batch. Deleted and
+// recurring template tasks are left out, as are tasks already here, so the
+// same export can be imported again.
+func Parse(store *model.Store, r io.Reader) (*model.Batch, error) {
+	var tasks []twTask
+	if err := json.NewDecoder(r).Decode(&tasks); err != nil {
+		return nil, err
+	}
+
+	batch := &model.Batch{}
+	seen := make(map[string]bool)
+	for _, tw := range tasks {
+		if tw.Status == "deleted" || tw.Status == "recurring" || strings.TrimSpace(tw.Description) == "" {
+			continue
+		}
+		if tw.UUID != "" && (store.GetTask(tw.UUID) != nil || seen[tw.UUID]) {
+			continue
+		}
+		seen[tw.UUID] = true
+		batch.Tasks = append(batch.Tasks, newTask(store, batch, tw))
+	}
+	return batch, nil
+}
+
+func newTask(store *model.Store, batch *model.Batch, tw twTask) *model.Task {
+	category := model.Category(tw.Category)
+	if !category.Valid() {
+		category = urgencyCategory(tw.Urgency)
+	}
+
+	var notes []string
+	for _, a := range tw.Annotations {
+		notes = append(notes, a.Description)
+	}
+	task := model.NewTask(withTags(tw.Description, tw.Tags), strings.Join(notes, "\n\n"), category)
+	if tw.UUID != "" {
+		task.ID = tw.UUID
+	}
+	task.Priority = priorities[tw.Priority]
+
+	if t, ok := parseTime(tw.Entry); ok {
+		task.CreatedAt = t
+	}
+	if t, ok := parseTime(tw.Modified); ok {
+		task.UpdatedAt = t
+	}
+	if t, ok := parseTime(tw.Due); ok {
+		task.Due = &t
+	}
+	if tw.Status == "completed" {
+		task.Completed = true
+		end, ok := parseTime(tw.End)
+		if !ok {
+			end = task.UpdatedAt
+		}
+		task.CompletedAt = &end
+	}
+
+	projects := tw.Projects
+	if len(projects) == 0 && tw.Project != "" {
+		projects = []string{tw.Project}
+	}
+	for _, name := range projects {
+		project := batch.Project(store, name)
+		if !task.HasProject(project.ID) {
+			task.ProjectIDs = append(task.ProjectIDs, project.ID)
+		}
+	}
+	return task
+}
+
+func urgencyCategory(urgency float64) model.Category {
+	switch {
+	case urgency >= todayUrgency:
+		return model.CategoryToday
+	case urgency >= weekUrgency:
+		return model.CategoryWeek
+	case urgency >= notUrgentUrgency:
+		return model.CategoryNotUrgent
+	default:
+		return model.CategoryGeneral
+	}
+}
+
+// withTags adds the tags missing from a description to it as @contexts,
+// where t7t shows them.
+func withTags(description string, tags []string) string {
+	present := make(map[string]bool)
+	for _, m := range contextRegex.FindAllStringSubmatch(description, -1) {
+		present[strings.ToLower(m[1])] = true
+	}
+	for _, tag := range tags {
+		if !present[strings.ToLower(tag)] {
+			description += " @" + tag
+		}
+	}
+	return strings.TrimSpace(description)
+}
+
+func parseTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{timeFormat, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// exportPriority maps a priority letter back to Taskwarrior's, where every
+// letter after C is as low as it gets.
+func exportPriority(letter string) string {
+	if letter == "" {
+		return ""
+	}
+	for twPriority, l := range priorities {
+		if l == letter {
+			return twPriority
+		}
+	}
+	return "L"
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// Export writes every task in the format `task import` reads, one task per
+// line like `task export` does.
+func Export(store *model.Store, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[\n")
+	for i, task := range store.Tasks {
+		data, err := json.Marshal(toTaskwarrior(task, store.GetProjectNames(task.ProjectIDs)))
+		if err != nil {
+			return err
+		}
+		bw.Write(data)
+		if i < len(store.Tasks)-1 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n")
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
+func toTaskwarrior(task *model.Task, projects []string) twTask {
+	tw := twTask{
+		UUID:        task.ID,
+		Description: task.Name,
+		Status:      "pending",
+		Entry:       formatTime(task.CreatedAt),
+		Modified:    formatTime(task.UpdatedAt),
+		Category:    string(task.Category),
+	}
+	for _, m := range contextRegex.FindAllStringSubmatch(task.Name, -1) {
+		tw.Tags = append(tw.Tags, m[1])
+	}
+	tw.Priority = exportPriority(task.Priority)
+	if task.Completed {
+		tw.Status = "completed"
+		end := task.UpdatedAt
+		if task.CompletedAt != nil {
+			end = *task.CompletedAt
+		}
+		tw.End = formatTime(end)
+	}
+	if task.Due != nil {
+		tw.Due = formatTime(*task.Due)
+	}
+	if len(projects) > 0 {
+		tw.Project = projects[0]
+	}
+	if len(projects) > 1 {
+		tw.Projects = projects
+	}
+	if task.Description != "" {
+		tw.Annotations = []twAnnotation{{Entry: formatTime(task.UpdatedAt), Description: task.Description}}
+	}
+	return tw
+}
@@ -0,0 +1,278 @@
+// Package todotxt converts tasks to and from the todo.txt format
+// (https://github.com/todotxt/todo.txt). @contexts stay in the task name,
+// where t7t highlights them, +projects become project associations and the
+// fields todo.txt has no syntax for use key:value extensions. Words of a name
+// that would read as one of those are written with a backslash in front.
+package todotxt
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"t7t/internal/model"
+)
+
+const (
+	dateFormat = "2006-01-02"
+
+	dueKey      = "due"
+	categoryKey = "cat"
+
+	// idKey lets an edited export update the tasks it came from.
+	idKey = "id"
+
+	// descKey carries the description, query-escaped so its spaces and
+	// line breaks don't end the field or the line.
+	descKey = "desc"
+
+	// priorityKey keeps the priority of completed tasks, which by convention
+	// lose their (A) prefix.
+	priorityKey = "pri"
+
+	// importCategory is where tasks without a cat: key go. It isn't written
+	// back on export, so files without categories round-trip unchanged.
+	importCategory = model.CategoryGeneral
+)
+
+// Parse reads a todo.txt file into a batch of tasks, along with the projects
+// they name that don't exist yet. Lines with the id: of a task in the store
+// update that task.
+func Parse(store *model.Store, r io.Reader) (*model.Batch, error) {
+	batch := &model.Batch{}
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		task, projectNames := parseLine(scanner.Text())
+		if task == nil {
+			continue
+		}
+		// Like the CSV id column, an id: only counts when it names a task
+		// here or is a UUID, and a repeated one makes a copy.
+		if _, err := uuid.Parse(task.ID); seen[task.ID] || err != nil && store.GetTask(task.ID) == nil {
+			task.ID = uuid.New().String()
+		}
+		seen[task.ID] = true
+
+		for _, name := range projectNames {
+			project := findProject(store, name)
+			if project == nil {
+				project = batch.Project(store, name)
+			}
+			if !task.HasProject(project.ID) {
+				task.ProjectIDs = append(task.ProjectIDs, project.ID)
+			}
+		}
+		if store.GetTask(task.ID) != nil {
+			batch.Update(store, task)
+		} else {
+			batch.Tasks = append(batch.Tasks, task)
+		}
+	}
+	return batch, scanner.Err()
+}
+
+// findProject matches a +project to an existing project, whose name may have
+// had its spaces replaced on export.
+func findProject(store *model.Store, name string) *model.Project {
+	if p := store.ProjectByName(name); p != nil {
+		return p
+	}
+	return store.ProjectByName(strings.ReplaceAll(name, "_", " "))
+}
+
+// parseLine turns a todo.txt line into a task and the names of its projects.
+// Blank lines yield nil.
+func parseLine(line string) (*model.Task, []string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	task := model.NewTask("", "", importCategory)
+	i := 0
+	date := func() (time.Time, bool) {
+		if i >= len(fields) {
+			return time.Time{}, false
+		}
+		d, err := time.ParseInLocation(dateFormat, fields[i], time.Local)
+		if err == nil {
+			i++
+		}
+		return d, err == nil
+	}
+
+	if fields[0] == "x" {
+		i++
+		task.Completed = true
+		completedAt := time.Now()
+		if d, ok := date(); ok {
+			completedAt = d
+			if created, ok := date(); ok {
+				task.CreatedAt = created
+			}
+		}
+		task.CompletedAt = &completedAt
+	} else {
+		if isPriority(fields[0]) {
+			task.Priority = fields[0][1:2]
+			i++
+		}
+		if created, ok := date(); ok {
+			task.CreatedAt = created
+		}
+	}
+
+	var words, projects []string
+	for _, field := range fields[i:] {
+		if word, ok := strings.CutPrefix(field, `\`); ok && special(word, len(words) == 0) {
+			words = append(words, word)
+			continue
+		}
+		if name, ok := projectName(field); ok {
+			projects = append(projects, name)
+			continue
+		}
+		if key, value, ok := strings.Cut(field, ":"); ok && applyExtension(task, key, value) {
+			continue
+		}
+		words = append(words, field)
+	}
+
+	task.Name = strings.Join(words, " ")
+	if task.Name == "" {
+		return nil, nil
+	}
+	return task, projects
+}
+
+// projectName reads a +project tag. Project names start with a letter, so
+// words like +1 stay in the task name.
+func projectName(word string) (string, bool) {
+	name, ok := strings.CutPrefix(word, "+")
+	first, _ := utf8.DecodeRuneInString(name)
+	return name, ok && unicode.IsLetter(first)
+}
+
+// special reports whether a word of a task name would be read as something
+// else: a project, a known extension, a date where the name starts, or an
+// escaped word.
+func special(word string, first bool) bool {
+	if _, ok := projectName(word); ok {
+		return true
+	}
+	if key, value, ok := strings.Cut(word, ":"); ok && applyExtension(&model.Task{}, key, value) {
+		return true
+	}
+	if _, err := time.Parse(dateFormat, word); err == nil && first {
+		return true
+	}
+	rest, ok := strings.CutPrefix(word, `\`)
+	return ok && special(rest, first)
+}
+
+// escapeName puts a backslash in front of the words of a name that would
+// otherwise be read back as something else.
+func escapeName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		if special(word, i == 0) {
+			words[i] = `\` + word
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// applyExtension sets the field a known key:value extension describes.
+// Unknown or invalid ones are left in the name so nothing is lost.
+func applyExtension(task *model.Task, key, value string) bool {
+	switch key {
+	case dueKey:
+		due, err := time.ParseInLocation(dateFormat, value, time.Local)
+		if err != nil {
+			return false
+		}
+		task.Due = &due
+	case categoryKey:
+		category := model.Category(value)
+		if !category.Valid() {
+			return false
+		}
+		task.Category = category
+	case priorityKey:
+		if len(value) != 1 || value[0] < 'A' || value[0] > 'Z' {
+			return false
+		}
+		task.Priority = value
+	case idKey:
+		if value == "" {
+			return false
+		}
+		task.ID = value
+	case descKey:
+		description, err := url.QueryUnescape(value)
+		if err != nil {
+			return false
+		}
+		task.Description = description
+	default:
+		return false
+	}
+	return true
+}
+
+func isPriority(field string) bool {
+	return len(field) == 3 && field[0] == '(' && field[2] == ')' && field[1] >= 'A' && field[1] <= 'Z'
+}
+
+// Export writes every task as a todo.txt line.
+func Export(store *model.Store, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, task := range store.Tasks {
+		bw.WriteString(FormatTask(task, store.GetProjectNames(task.ProjectIDs)))
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// FormatTask renders a task as a todo.txt line, given the names of its
+// projects.
+func FormatTask(task *model.Task, projectNames []string) string {
+	var parts []string
+	switch {
+	case task.Completed && task.CompletedAt != nil:
+		parts = append(parts, "x", task.CompletedAt.Local().Format(dateFormat), task.CreatedAt.Local().Format(dateFormat))
+	case task.Completed:
+		// Without a completion date, a creation date would be read as one.
+		parts = append(parts, "x")
+	case task.Priority != "":
+		parts = append(parts, "("+task.Priority+")", task.CreatedAt.Local().Format(dateFormat))
+	default:
+		parts = append(parts, task.CreatedAt.Local().Format(dateFormat))
+	}
+
+	parts = append(parts, escapeName(task.Name))
+	for _, name := range projectNames {
+		parts = append(parts, "+"+strings.ReplaceAll(name, " ", "_"))
+	}
+	if task.Due != nil {
+		parts = append(parts, dueKey+":"+task.Due.Local().Format(dateFormat))
+	}
+	if task.Category != importCategory {
+		parts = append(parts, categoryKey+":"+string(task.Category))
+	}
+	if task.Completed && task.Priority != "" {
+		parts = append(parts, priorityKey+":"+task.Priority)
+	}
+	if task.Description != "" {
+		parts = append(parts, descKey+":"+url.QueryEscape(task.Description))
+	}
+	parts = append(parts, idKey+":"+task.ID)
+	return strings.Join(parts, " ")
+}
@@ -1,11 +1,15 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	"t7t/internal/config"
+	"t7t/internal/daemon"
 	"t7t/internal/i18n"
 	"t7t/internal/keys"
 	"t7t/internal/model"
@@ -27,6 +31,9 @@ type ViewMode int
 const (
 	ViewTasks ViewMode = iota
 	ViewProjects
+	ViewReview
+	ViewStats
+	ViewFocus
 )
 
 const confettiDuration = 1300 * time.Millisecond
@@ -40,6 +47,35 @@ func animateConfetti() tea.Cmd {
 	})
 }
 
+type timerTickMsg time.Time
+
+func tickTimer() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return timerTickMsg(t)
+	})
+}
+
+const saveRetryInterval = 5 * time.Second
+
+type saveRetryMsg time.Time
+
+func retrySave() tea.Cmd {
+	return tea.Tick(saveRetryInterval, func(t time.Time) tea.Msg {
+		return saveRetryMsg(t)
+	})
+}
+
+const storeWatchInterval = time.Second
+
+type storeWatchMsg time.Time
+
+// watchStore polls the data file for changes made by other processes.
+func watchStore() tea.Cmd {
+	return tea.Tick(storeWatchInterval, func(t time.Time) tea.Msg {
+		return storeWatchMsg(t)
+	})
+}
+
 type ModalType int
 
 const (
@@ -52,6 +88,11 @@ const (
 	ModalHelp
 	ModalConfirmDelete
 	ModalLanguage
+	ModalDependencies
+	ModalUnsavedQuit
+	ModalWorkspace
+	ModalNewWorkspace
+	ModalBackups
 )
 
 type App struct {
@@ -72,6 +113,7 @@ type App struct {
 	taskFormProjectsViewport viewport.Model
 	projectsModalViewport    viewport.Model
 	helpModalViewport        viewport.Model
+	statsViewport            viewport.Model
 
 	detailFocused bool
 
@@ -82,6 +124,9 @@ type App struct {
 	descInput     textarea.Model
 	selectedProjs map[string]bool
 
+	selectedBlockers map[string]bool
+	blockerIndex     int
+
 	help       help.Model
 	showHelp   bool
 	width      int
@@ -103,9 +148,29 @@ type App struct {
 	confettiEndTime time.Time
 
 	languageIndex int
+
+	workspace      string
+	workspaces     []string
+	workspaceIndex int
+
+	backups       []model.Backup
+	backupIndex   int
+	backupConfirm bool
+
+	review reviewSession
+
+	timerTicking bool
+
+	saveErr      error
+	saveRetrying bool
+
+	daemon        *daemon.Client
+	daemonVersion uint64
+
+	focus focusSession
 }
 
-func NewApp(store *model.Store) *App {
+func NewApp(store *model.Store, workspace string) *App {
 	msg := i18n.Get()
 
 	nameInput := textinput.New()
@@ -130,26 +195,28 @@ func NewApp(store *model.Store) *App {
 	)
 
 	return &App{
-		store:    store,
-		viewMode: ViewTasks,
-		tabs:     []string{msg.TabToday, msg.TabWeek, msg.TabNotUrgent, msg.TabGeneral},
+		store:     store,
+		workspace: workspace,
+		viewMode:  ViewTasks,
+		tabs:      []string{msg.TabToday, msg.TabWeek, msg.TabNotUrgent, msg.TabGeneral},
 		categories: []model.Category{
 			model.CategoryToday,
 			model.CategoryWeek,
 			model.CategoryNotUrgent,
 			model.CategoryGeneral,
 		},
-		activeTab:     0,
-		taskIndex:     0,
-		projectIndex:  0,
-		modal:         ModalNone,
-		nameInput:     nameInput,
-		descInput:     descInput,
-		selectedProjs: make(map[string]bool),
-		help:          h,
-		showHelp:      false,
-		mdRenderer:    mdRenderer,
-		focusedInput:  0,
+		activeTab:        0,
+		taskIndex:        0,
+		projectIndex:     0,
+		modal:            ModalNone,
+		nameInput:        nameInput,
+		descInput:        descInput,
+		selectedProjs:    make(map[string]bool),
+		selectedBlockers: make(map[string]bool),
+		help:             h,
+		showHelp:         false,
+		mdRenderer:       mdRenderer,
+		focusedInput:     0,
 		confettiSystem: &simulation.System{
 			Particles: []*simulation.Particle{},
 			Frame:     simulation.Frame{},
@@ -162,6 +229,7 @@ func NewApp(store *model.Store) *App {
 		taskFormProjectsViewport: viewport.New(0, 0),
 		projectsModalViewport:    viewport.New(0, 0),
 		helpModalViewport:        viewport.New(0, 0),
+		statsViewport:            viewport.New(0, 0),
 		lastModal:                ModalNone,
 		detailFocused:            false,
 		languageIndex:            0,
@@ -177,7 +245,17 @@ func (a *App) updateTexts() {
 }
 
 func (a *App) Init() tea.Cmd {
-	return nil
+	return tea.Batch(a.ensureTimerTick(), watchStore(), a.connectDaemon())
+}
+
+// ensureTimerTick starts the once-per-second refresh of the status bar clock
+// while a timer is running, making sure only one tick loop is active.
+func (a *App) ensureTimerTick() tea.Cmd {
+	if a.timerTicking || a.store.RunningTimer() == nil {
+		return nil
+	}
+	a.timerTicking = true
+	return tickTimer()
 }
 
 func (a *App) startConfetti() tea.Cmd {
@@ -200,7 +278,105 @@ func (a *App) checkAllTodayTasksCompleted() bool {
 	return true
 }
 
+// persist reports a failed store operation in the status bar. The store keeps
+// the change in memory and marks itself dirty, and Update schedules retries
+// until a save succeeds.
+func (a *App) persist(err error) bool {
+	if err == nil {
+		return true
+	}
+	a.saveErr = err
+	a.statusMsg = fmt.Sprintf(i18n.Get().StatusSaveFailed, err)
+	a.statusErr = true
+	return false
+}
+
+func (a *App) ensureSaveRetry() tea.Cmd {
+	if a.saveRetrying || !a.store.Dirty() {
+		return nil
+	}
+	a.saveRetrying = true
+	return retrySave()
+}
+
+// reloadStore merges changes other processes wrote to the data file and keeps
+// the cursor on the task and project that were selected before.
+func (a *App) reloadStore() tea.Cmd {
+	m := i18n.Get()
+
+	var taskID, projectID string
+	if tasks := a.store.GetTasksByCategory(a.categories[a.activeTab]); a.taskIndex < len(tasks) {
+		taskID = tasks[a.taskIndex].ID
+	}
+	if projects := a.store.GetProjects(); a.projectIndex < len(projects) {
+		projectID = projects[a.projectIndex].ID
+	}
+
+	result, err := a.store.Reload()
+	if err != nil {
+		a.statusMsg = fmt.Sprintf(m.StatusReloadFailed, err)
+		a.statusErr = true
+		return nil
+	}
+	if !result.Changed {
+		return nil
+	}
+
+	a.taskIndex = selectionIndex(a.store.GetTasksByCategory(a.categories[a.activeTab]), taskID, a.taskIndex,
+		func(t *model.Task) string { return t.ID })
+	a.projectIndex = selectionIndex(a.store.GetProjects(), projectID, a.projectIndex,
+		func(p *model.Project) string { return p.ID })
+
+	if len(result.Conflicts) > 0 {
+		a.statusMsg = fmt.Sprintf(m.StatusReloadConflicts, strings.Join(result.Conflicts, ", "))
+		a.statusErr = true
+	} else {
+		a.statusMsg = m.StatusReloaded
+	}
+	return a.ensureTimerTick()
+}
+
+// selectionIndex finds the item with the given ID, falling back to the old
+// index clamped to the list when the item is gone.
+func selectionIndex[T any](items []*T, id string, fallback int, itemID func(*T) string) int {
+	for i, item := range items {
+		if itemID(item) == id {
+			return i
+		}
+	}
+	if fallback >= len(items) {
+		fallback = len(items) - 1
+	}
+	if fallback < 0 {
+		fallback = 0
+	}
+	return fallback
+}
+
+// quit leaves the application unless there are changes that could not be
+// saved, in which case the user has to decide what to do with them first.
+func (a *App) quit() (tea.Model, tea.Cmd) {
+	if a.store.Dirty() {
+		a.modal = ModalUnsavedQuit
+		return a, nil
+	}
+	return a, tea.Quit
+}
+
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := a.update(msg)
+	// After update, so the warning replaces the message of the change saved.
+	if err := a.store.HookError(); err != nil {
+		a.statusMsg = fmt.Sprintf(i18n.Get().StatusSaveHookFailed, err)
+		a.statusErr = true
+	}
+	if retry := a.ensureSaveRetry(); retry != nil {
+		return model, tea.Batch(cmd, retry)
+	}
+	return model, cmd
+}
+
+func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -253,14 +429,61 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, nil
 
+	case timerTickMsg:
+		if a.store.RunningTimer() == nil {
+			a.timerTicking = false
+			return a, nil
+		}
+		return a, tickTimer()
+
+	case pomodoroTickMsg:
+		return a, a.updatePomodoro()
+
+	case storeWatchMsg:
+		return a, tea.Batch(a.reloadStore(), watchStore())
+
+	case daemonChangeMsg:
+		return a, a.handleDaemonChange(msg)
+
+	case saveRetryMsg:
+		a.saveRetrying = false
+		if !a.store.Dirty() {
+			return a, nil
+		}
+		if err := a.store.Save(); err != nil {
+			a.saveErr = err
+			return a, nil
+		}
+		a.saveErr = nil
+		a.statusMsg = i18n.Get().StatusSaveRecovered
+		a.statusErr = false
+		if a.modal == ModalUnsavedQuit {
+			a.modal = ModalNone
+		}
+		return a, nil
+
 	case tea.KeyMsg:
+		// Errors stay visible until the next key press
+		if a.statusErr {
+			a.statusMsg = ""
+			a.statusErr = false
+		}
+
 		if a.modal != ModalNone {
 			return a.handleModalInput(msg)
 		}
 
+		if a.viewMode == ViewReview {
+			return a.handleReviewInput(msg)
+		}
+
+		if a.viewMode == ViewFocus {
+			return a.handleFocusInput(msg)
+		}
+
 		switch {
 		case key.Matches(msg, keys.Keys.Quit):
-			return a, tea.Quit
+			return a.quit()
 
 		case key.Matches(msg, keys.Keys.Help):
 			if a.modal == ModalNone {
@@ -283,6 +506,33 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return a, nil
 
+		case key.Matches(msg, keys.Keys.Workspace):
+			if a.modal == ModalNone {
+				a.openWorkspaceModal()
+			}
+			return a, nil
+
+		case key.Matches(msg, keys.Keys.Backups):
+			if a.modal == ModalNone {
+				a.openBackupsModal()
+			}
+			return a, nil
+
+		case key.Matches(msg, keys.Keys.Export):
+			if a.viewMode == ViewTasks || a.viewMode == ViewProjects {
+				a.exportMarkdown()
+			}
+			return a, nil
+
+		case key.Matches(msg, keys.Keys.Stats):
+			if a.viewMode == ViewStats {
+				a.viewMode = ViewTasks
+			} else {
+				a.viewMode = ViewStats
+				a.statsViewport.GotoTop()
+			}
+			return a, nil
+
 		case msg.String() == "P":
 			if a.viewMode == ViewTasks {
 				a.viewMode = ViewProjects
@@ -293,9 +543,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
-		if a.viewMode == ViewTasks {
+		switch a.viewMode {
+		case ViewTasks:
 			return a.handleTasksInput(msg)
-		} else {
+		case ViewStats:
+			return a.handleStatsInput(msg)
+		default:
 			return a.handleProjectsInput(msg)
 		}
 	}
@@ -430,9 +683,14 @@ func (a *App) handleTasksInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(tasks) > 0 && a.taskIndex < len(tasks) {
 			task := tasks[a.taskIndex]
 			task.ToggleComplete()
-			a.store.UpdateTask(task)
+			if !a.persist(a.store.UpdateTask(task)) {
+				return a, nil
+			}
 			if task.Completed {
 				a.statusMsg = m.StatusTaskCompleted
+				if unblocked := a.unblockedNotice(task); unblocked != "" {
+					a.statusMsg = unblocked
+				}
 				if a.activeTab == 0 && a.checkAllTodayTasksCompleted() {
 					a.statusMsg = m.StatusAllTodayDone
 					return a, a.startConfetti()
@@ -472,6 +730,49 @@ func (a *App) handleTasksInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return a, nil
 
+	case key.Matches(msg, keys.Keys.Dependencies):
+		if len(tasks) > 0 && a.taskIndex < len(tasks) {
+			task := tasks[a.taskIndex]
+			a.modal = ModalDependencies
+			a.editingTaskID = task.ID
+			a.selectedBlockers = make(map[string]bool)
+			for _, id := range task.BlockedBy {
+				a.selectedBlockers[id] = true
+			}
+			a.blockerIndex = 0
+			a.projectsModalViewport.GotoTop()
+		}
+		return a, nil
+
+	case key.Matches(msg, keys.Keys.Timer):
+		if len(tasks) > 0 && a.taskIndex < len(tasks) {
+			task := tasks[a.taskIndex]
+			if task.RunningEntry() != nil {
+				_, elapsed, err := a.store.StopTimer()
+				if a.persist(err) {
+					a.statusMsg = fmt.Sprintf(m.StatusTimerStopped, model.FormatDuration(elapsed))
+				}
+				return a, nil
+			}
+			if a.persist(a.store.StartTimer(task)) {
+				a.statusMsg = m.StatusTimerStarted
+			}
+			return a, a.ensureTimerTick()
+		}
+		return a, nil
+
+	case key.Matches(msg, keys.Keys.Focus):
+		if len(tasks) > 0 && a.taskIndex < len(tasks) {
+			return a.startFocus(tasks[a.taskIndex])
+		}
+		return a, nil
+
+	case key.Matches(msg, keys.Keys.Review):
+		return a.startReview([]model.Category{a.categories[a.activeTab]})
+
+	case key.Matches(msg, keys.Keys.ReviewAll):
+		return a.startReview(a.categories)
+
 	case key.Matches(msg, keys.Keys.MoveToday):
 		return a.moveTask(tasks, model.CategoryToday)
 	case key.Matches(msg, keys.Keys.MoveWeek):
@@ -485,16 +786,43 @@ func (a *App) handleTasksInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// unblockedNotice lists the tasks that became workable after task was
+// completed, or returns an empty string when none did.
+func (a *App) unblockedNotice(task *model.Task) string {
+	unblocked := a.store.NewlyUnblocked(task)
+	if len(unblocked) == 0 {
+		return ""
+	}
+	var names []string
+	for _, t := range unblocked {
+		names = append(names, t.Name)
+	}
+	return fmt.Sprintf(i18n.Get().StatusUnblocked, strings.Join(names, ", "))
+}
+
+// dependencyCandidates returns the tasks that can block the task being edited.
+func (a *App) dependencyCandidates() []*model.Task {
+	var candidates []*model.Task
+	for _, t := range a.store.Tasks {
+		if t.ID != a.editingTaskID {
+			candidates = append(candidates, t)
+		}
+	}
+	return candidates
+}
+
 func (a *App) moveTask(tasks []*model.Task, category model.Category) (tea.Model, tea.Cmd) {
 	m := i18n.Get()
 	if len(tasks) > 0 && a.taskIndex < len(tasks) {
 		task := tasks[a.taskIndex]
 		task.SetCategory(category)
-		a.store.UpdateTask(task)
+		saved := a.persist(a.store.UpdateTask(task))
 		if a.taskIndex >= len(tasks)-1 && a.taskIndex > 0 {
 			a.taskIndex--
 		}
-		a.statusMsg = fmt.Sprintf(m.StatusTaskMoved, model.CategoryString(category))
+		if saved {
+			a.statusMsg = fmt.Sprintf(m.StatusTaskMoved, model.CategoryString(category))
+		}
 	}
 	return a, nil
 }
@@ -561,7 +889,9 @@ func (a *App) handleProjectsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(projects) > 0 && a.projectIndex < len(projects) {
 			proj := projects[a.projectIndex]
 			proj.ToggleComplete()
-			a.store.UpdateProject(proj)
+			if !a.persist(a.store.UpdateProject(proj)) {
+				return a, nil
+			}
 			if proj.Completed {
 				a.statusMsg = m.StatusProjectCompleted
 			} else {
@@ -590,11 +920,25 @@ func (a *App) handleModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if keyStr == "shift+enter" || keyStr == "ctrl+s" {
 		if a.modal == ModalNewTask || a.modal == ModalEditTask ||
 			a.modal == ModalNewProject || a.modal == ModalEditProject ||
-			a.modal == ModalAssociateProjects {
+			a.modal == ModalAssociateProjects || a.modal == ModalDependencies {
 			return a.confirmModal()
 		}
 	}
 
+	if a.modal == ModalUnsavedQuit {
+		switch keyStr {
+		case "r", "R":
+			if err := a.store.Save(); err != nil {
+				a.saveErr = err
+				return a, nil
+			}
+			return a, tea.Quit
+		case "q", "Q":
+			return a, tea.Quit
+		}
+		return a, nil
+	}
+
 	if a.modal == ModalHelp {
 		switch {
 		case key.Matches(msg, keys.Keys.Down):
@@ -623,28 +967,45 @@ func (a *App) handleModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a, nil
 	}
 
+	if a.modal == ModalBackups {
+		return a.handleBackupsInput(msg)
+	}
+
+	if a.modal == ModalWorkspace || a.modal == ModalNewWorkspace {
+		return a.handleWorkspaceInput(msg)
+	}
+
 	if a.modal == ModalConfirmDelete {
 		switch msg.String() {
 		case "y", "Y", "s", "S", "enter":
 			if a.deleteType == "task" {
-				a.store.DeleteTask(a.deleteID)
+				saved := a.persist(a.store.DeleteTask(a.deleteID))
 				tasks := a.store.GetTasksByCategory(a.categories[a.activeTab])
 				if a.taskIndex >= len(tasks) && a.taskIndex > 0 {
 					a.taskIndex--
 				}
-				a.statusMsg = m.StatusTaskDeleted
+				if saved {
+					a.statusMsg = m.StatusTaskDeleted
+				}
+				if a.viewMode == ViewReview {
+					a.review.record(reviewDeleted, a.deleteName, "")
+				}
 			} else if a.deleteType == "project" {
-				a.store.DeleteProject(a.deleteID)
+				saved := a.persist(a.store.DeleteProject(a.deleteID))
 				projects := a.store.GetProjects()
 				if a.projectIndex >= len(projects) && a.projectIndex > 0 {
 					a.projectIndex--
 				}
-				a.statusMsg = m.StatusProjectDeleted
+				if saved {
+					a.statusMsg = m.StatusProjectDeleted
+				}
 			} else if a.deleteType == "completed" {
 				category := model.Category(a.deleteID)
-				a.store.DeleteCompletedTasks(category)
+				saved := a.persist(a.store.DeleteCompletedTasks(category))
 				a.taskIndex = 0
-				a.statusMsg = m.StatusCompletedDeleted
+				if saved {
+					a.statusMsg = m.StatusCompletedDeleted
+				}
 			}
 			a.modal = ModalNone
 			a.deleteType = ""
@@ -695,6 +1056,40 @@ func (a *App) handleModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a, nil
 	}
 
+	if a.modal == ModalDependencies {
+		candidates := a.dependencyCandidates()
+		switch {
+		case key.Matches(msg, keys.Keys.Down):
+			if len(candidates) > 0 {
+				oldIndex := a.blockerIndex
+				a.blockerIndex = (a.blockerIndex + 1) % len(candidates)
+				if oldIndex == len(candidates)-1 && a.blockerIndex == 0 {
+					a.projectsModalViewport.GotoTop()
+				} else {
+					a.projectsModalViewport.LineDown(1)
+				}
+			}
+		case key.Matches(msg, keys.Keys.Up):
+			if len(candidates) > 0 {
+				oldIndex := a.blockerIndex
+				a.blockerIndex = (a.blockerIndex - 1 + len(candidates)) % len(candidates)
+				if oldIndex == 0 && a.blockerIndex == len(candidates)-1 {
+					a.projectsModalViewport.GotoBottom()
+				} else {
+					a.projectsModalViewport.LineUp(1)
+				}
+			}
+		case msg.String() == " ":
+			if len(candidates) > 0 && a.blockerIndex < len(candidates) {
+				t := candidates[a.blockerIndex]
+				a.selectedBlockers[t.ID] = !a.selectedBlockers[t.ID]
+			}
+		case key.Matches(msg, keys.Keys.Enter):
+			return a.confirmModal()
+		}
+		return a, nil
+	}
+
 	if a.modal == ModalNewProject || a.modal == ModalEditProject {
 		if key.Matches(msg, keys.Keys.Enter) {
 			return a.confirmModal()
@@ -805,12 +1200,8 @@ func (a *App) confirmModal() (tea.Model, tea.Cmd) {
 
 	switch a.modal {
 	case ModalNewTask:
-		name := strings.TrimSpace(a.nameInput.Value())
-		if name != "" {
-			task := model.NewTask(name, a.descInput.Value(), a.categories[a.activeTab])
-			task.ProjectIDs = getSelectedProjectIDs()
-			a.store.AddTask(task)
-			a.statusMsg = m.StatusTaskCreated
+		if !a.addQuickTask(getSelectedProjectIDs()) {
+			return a, nil
 		}
 
 	case ModalEditTask:
@@ -818,9 +1209,12 @@ func (a *App) confirmModal() (tea.Model, tea.Cmd) {
 		if name != "" && a.editingTaskID != "" {
 			if task := a.store.GetTask(a.editingTaskID); task != nil {
 				task.Update(name, a.descInput.Value())
-				task.ProjectIDs = getSelectedProjectIDs()
-				a.store.UpdateTask(task)
-				a.statusMsg = m.StatusTaskUpdated
+				if ids := getSelectedProjectIDs(); !sameProjects(task.ProjectIDs, ids) {
+					task.SetProjects(ids)
+				}
+				if a.persist(a.store.UpdateTask(task)) {
+					a.statusMsg = m.StatusTaskUpdated
+				}
 			}
 		}
 
@@ -828,8 +1222,9 @@ func (a *App) confirmModal() (tea.Model, tea.Cmd) {
 		name := strings.TrimSpace(a.nameInput.Value())
 		if name != "" {
 			proj := model.NewProject(name)
-			a.store.AddProject(proj)
-			a.statusMsg = m.StatusProjectCreated
+			if a.persist(a.store.AddProject(proj)) {
+				a.statusMsg = m.StatusProjectCreated
+			}
 		}
 
 	case ModalEditProject:
@@ -837,8 +1232,9 @@ func (a *App) confirmModal() (tea.Model, tea.Cmd) {
 		if name != "" && a.editingProjectID != "" {
 			if proj := a.store.GetProject(a.editingProjectID); proj != nil {
 				proj.Update(name)
-				a.store.UpdateProject(proj)
-				a.statusMsg = m.StatusProjectUpdated
+				if a.persist(a.store.UpdateProject(proj)) {
+					a.statusMsg = m.StatusProjectUpdated
+				}
 			}
 		}
 
@@ -851,9 +1247,29 @@ func (a *App) confirmModal() (tea.Model, tea.Cmd) {
 						projectIDs = append(projectIDs, pid)
 					}
 				}
-				task.SetProjects(projectIDs)
-				a.store.UpdateTask(task)
-				a.statusMsg = m.StatusProjectsAssoc
+				if !sameProjects(task.ProjectIDs, projectIDs) {
+					task.SetProjects(projectIDs)
+				}
+				if a.persist(a.store.UpdateTask(task)) {
+					a.statusMsg = m.StatusProjectsAssoc
+				}
+			}
+		}
+
+	case ModalDependencies:
+		if task := a.store.GetTask(a.editingTaskID); task != nil {
+			var blockerIDs []string
+			for _, t := range a.dependencyCandidates() {
+				if a.selectedBlockers[t.ID] {
+					blockerIDs = append(blockerIDs, t.ID)
+				}
+			}
+			err := a.store.SetBlockers(task, blockerIDs)
+			if errors.Is(err, model.ErrDependencyCycle) {
+				a.statusMsg = m.StatusDependencyCycle
+				a.statusErr = true
+			} else if a.persist(err) {
+				a.statusMsg = m.StatusDependenciesSet
 			}
 		}
 	}
@@ -862,6 +1278,12 @@ func (a *App) confirmModal() (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// sameProjects reports whether two lists hold the same project IDs, in any
+// order, so an unchanged selection isn't recorded as an edit.
+func sameProjects(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
+
 func (a *App) View() string {
 	if a.width == 0 {
 		return i18n.Get().Loading
@@ -869,14 +1291,23 @@ func (a *App) View() string {
 
 	var content string
 
-	if a.viewMode == ViewTasks {
+	switch a.viewMode {
+	case ViewTasks:
 		content = a.viewTasks()
-	} else {
+	case ViewReview:
+		content = a.viewReview()
+	case ViewStats:
+		content = a.viewStats()
+	case ViewFocus:
+		content = a.viewFocus()
+	default:
 		content = a.viewProjects()
 	}
 
 	if a.modal != ModalNone {
 		content = a.viewModal(content)
+	} else if banner := a.renderSaveBanner(); banner != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, banner, content)
 	}
 
 	if a.showConfetti {
@@ -933,7 +1364,7 @@ func (a *App) viewTasks() string {
 	panelBorderPadding := 4 // border (2) + padding (2) from ListPanelStyle
 
 	headerHeight := logoHeight + tabsHeight + gaps
-	contentHeight := a.height - headerHeight - statusHeight - panelBorderPadding
+	contentHeight := a.bodyHeight() - headerHeight - statusHeight - panelBorderPadding
 
 	if contentHeight < 5 {
 		contentHeight = 5
@@ -946,6 +1377,7 @@ func (a *App) viewTasks() string {
 	tasks := a.store.GetTasksByCategory(a.categories[a.activeTab])
 
 	listContent := a.renderTaskList(tasks, listWidth, contentHeight)
+	a.taskListViewport.Height = contentHeight
 	a.taskListViewport.SetContent(listContent)
 
 	listStyle := ListPanelStyle.Width(listWidth).Height(contentHeight)
@@ -959,6 +1391,7 @@ func (a *App) viewTasks() string {
 	listPanel := listStyle.Render(a.taskListViewport.View())
 
 	detailContent := a.renderTaskDetail(tasks, detailWidth, contentHeight)
+	a.taskDetailViewport.Height = contentHeight
 	a.taskDetailViewport.SetContent(detailContent)
 	detailPanel := detailStyle.Render(a.taskDetailViewport.View())
 
@@ -992,6 +1425,10 @@ func (a *App) renderTabs() string {
 
 	projStyle := InactiveTabStyle
 	tabs = append(tabs, TabGapStyle.Render(" | "), projStyle.Render("[P] "+m.KeyProjects))
+	tabs = append(tabs, TabGapStyle.Render(" | "), projStyle.Render("[S] "+m.KeyStats))
+	if a.workspace != config.DefaultWorkspace {
+		tabs = append(tabs, TabGapStyle.Render(" | "), projStyle.Render("[W] "+a.workspace))
+	}
 
 	return lipgloss.JoinHorizontal(lipgloss.Center, tabs...)
 }
@@ -1054,6 +1491,11 @@ func (a *App) renderTaskList(tasks []*model.Task, width, height int) string {
 		prefixLen := 8
 		availableWidth := width - prefixLen - 2
 
+		if !task.Completed && a.store.IsBlocked(task) {
+			line += BlockedStyle.Render(BlockedMarker)
+			availableWidth -= lipgloss.Width(BlockedMarker)
+		}
+
 		projectNames := a.store.GetProjectNames(task.ProjectIDs)
 		var projectsStr string
 		if len(projectNames) > 0 {
@@ -1098,7 +1540,11 @@ func (a *App) renderTaskDetail(tasks []*model.Task, width, height int) string {
 		return NormalItemStyle.Render(m.EmptyTaskDetail)
 	}
 
-	task := tasks[a.taskIndex]
+	return a.renderTaskDetailContent(tasks[a.taskIndex], width)
+}
+
+func (a *App) renderTaskDetailContent(task *model.Task, width int) string {
+	m := i18n.Get()
 	var b strings.Builder
 
 	b.WriteString(DetailTitleStyle.Width(width).Render(task.Name))
@@ -1107,11 +1553,25 @@ func (a *App) renderTaskDetail(tasks []*model.Task, width, height int) string {
 	b.WriteString(DetailLabelStyle.Render(m.LabelStatus))
 	if task.Completed {
 		b.WriteString(StatusMessageStyle.Render(m.LabelCompleted))
+	} else if a.store.IsBlocked(task) {
+		b.WriteString(BlockedStyle.Render(m.LabelBlocked))
 	} else {
 		b.WriteString(DetailValueStyle.Render(m.LabelPending))
 	}
 	b.WriteString("\n\n")
 
+	if task.Due != nil {
+		b.WriteString(DetailLabelStyle.Render(m.LabelDue))
+		b.WriteString(DetailValueStyle.Render(task.Due.Local().Format("2006-01-02")))
+		b.WriteString("\n\n")
+	}
+
+	if task.Priority != "" {
+		b.WriteString(DetailLabelStyle.Render(m.LabelPriority))
+		b.WriteString(DetailValueStyle.Render(task.Priority))
+		b.WriteString("\n\n")
+	}
+
 	b.WriteString(DetailLabelStyle.Render(m.LabelDescription))
 	b.WriteString("\n")
 	if task.Description == "" {
@@ -1128,6 +1588,18 @@ func (a *App) renderTaskDetail(tasks []*model.Task, width, height int) string {
 	}
 	b.WriteString("\n\n")
 
+	if tracked := task.TrackedTime(time.Now()); tracked > 0 {
+		b.WriteString(DetailLabelStyle.Render(m.LabelTimeTracked))
+		b.WriteString(DetailValueStyle.Render(model.FormatDuration(tracked)))
+		b.WriteString("\n\n")
+	}
+
+	if task.Pomodoros > 0 {
+		b.WriteString(DetailLabelStyle.Render(m.LabelPomodoros))
+		b.WriteString(DetailValueStyle.Render(fmt.Sprintf("%d", task.Pomodoros)))
+		b.WriteString("\n\n")
+	}
+
 	b.WriteString(DetailLabelStyle.Render(m.LabelProjects))
 	b.WriteString("\n")
 	projectNames := a.store.GetProjectNames(task.ProjectIDs)
@@ -1141,6 +1613,29 @@ func (a *App) renderTaskDetail(tasks []*model.Task, width, height int) string {
 		b.WriteString(DetailValueStyle.Render(strings.Join(projLines, "\n")))
 	}
 
+	dependencies := []struct {
+		label string
+		tasks []*model.Task
+	}{
+		{m.LabelBlockedBy, a.store.Blockers(task)},
+		{m.LabelBlocks, a.store.Dependents(task)},
+	}
+	for _, dep := range dependencies {
+		if len(dep.tasks) == 0 {
+			continue
+		}
+		b.WriteString("\n\n")
+		b.WriteString(DetailLabelStyle.Render(dep.label))
+		for _, t := range dep.tasks {
+			b.WriteString("\n")
+			if t.Completed {
+				b.WriteString(CompletedItemStyle.Render(CheckboxChecked + t.Name))
+			} else {
+				b.WriteString(DetailValueStyle.Render(CheckboxEmpty + t.Name))
+			}
+		}
+	}
+
 	return b.String()
 }
 
@@ -1159,7 +1654,7 @@ func (a *App) viewProjects() string {
 	panelBorderPadding := 4 // border (2) + padding (2) from ListPanelStyle
 
 	headerHeight := logoHeight + tabHeight + gaps
-	contentHeight := a.height - headerHeight - statusHeight - panelBorderPadding
+	contentHeight := a.bodyHeight() - headerHeight - statusHeight - panelBorderPadding
 
 	if contentHeight < 5 {
 		contentHeight = 5
@@ -1174,6 +1669,7 @@ func (a *App) viewProjects() string {
 	} else {
 		listWidth := a.width - 4
 		listContent := a.renderProjectList(projects, listWidth)
+		a.projectListViewport.Height = contentHeight
 		a.projectListViewport.SetContent(listContent)
 		panel = ListPanelStyle.Width(listWidth).Height(contentHeight).Render(a.projectListViewport.View())
 	}
@@ -1258,8 +1754,17 @@ func (a *App) renderStatusBar() string {
 	m := i18n.Get()
 	var parts []string
 
+	if task := a.store.RunningTimer(); task != nil {
+		elapsed := task.RunningEntry().Duration(time.Now())
+		parts = append(parts, TimerStyle.Render("● "+task.Name+" "+model.FormatClock(elapsed)))
+	}
+
 	if a.statusMsg != "" {
-		parts = append(parts, StatusMessageStyle.Render(a.statusMsg))
+		if a.statusErr {
+			parts = append(parts, StatusErrorStyle.Render(a.statusMsg))
+		} else {
+			parts = append(parts, StatusMessageStyle.Render(a.statusMsg))
+		}
 	}
 
 	var helpText string
@@ -1301,6 +1806,16 @@ func (a *App) viewModal(background string) string {
 		modalContent = a.renderConfirmDeleteModal()
 	case ModalLanguage:
 		modalContent = a.renderLanguageModal()
+	case ModalDependencies:
+		modalContent = a.renderDependenciesModal()
+	case ModalUnsavedQuit:
+		modalContent = a.renderUnsavedQuitModal()
+	case ModalBackups:
+		modalContent = a.renderBackupsModal()
+	case ModalWorkspace:
+		modalContent = a.renderWorkspaceModal()
+	case ModalNewWorkspace:
+		modalContent = a.renderNewWorkspaceForm()
 	}
 
 	modal := ModalStyle.Render(modalContent)
@@ -1335,7 +1850,17 @@ func (a *App) renderTaskForm(title string) string {
 	b.WriteString(InputLabelStyle.Render(nameLabel))
 	b.WriteString("\n")
 	b.WriteString(a.nameInput.View())
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if a.modal == ModalNewTask {
+		b.WriteString(a.quickAddPreview())
+		b.WriteString("\n")
+		// The status bar is hidden behind the form, so errors show here.
+		if a.statusErr {
+			b.WriteString(StatusErrorStyle.Render(a.statusMsg))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
 
 	descLabel := m.FormDescMarkdown
 	if a.focusedInput == 1 {
@@ -1465,6 +1990,53 @@ func (a *App) renderAssociateProjectsModal() string {
 	return a.projectsModalViewport.View()
 }
 
+func (a *App) renderDependenciesModal() string {
+	m := i18n.Get()
+	candidates := a.dependencyCandidates()
+
+	if len(candidates) == 0 {
+		return NormalItemStyle.Render(m.HintNoOtherTasks)
+	}
+
+	var b strings.Builder
+	b.WriteString(ModalTitleStyle.Render(m.ModalDependencies))
+	b.WriteString("\n\n")
+
+	for i, t := range candidates {
+		var line string
+
+		if i == a.blockerIndex {
+			line += CheckboxSelected
+		} else {
+			line += CheckboxNormal
+		}
+
+		if a.selectedBlockers[t.ID] {
+			line += CheckboxChecked
+		} else {
+			line += CheckboxEmpty
+		}
+
+		var style lipgloss.Style
+		if i == a.blockerIndex {
+			style = SelectedItemStyle
+		} else if t.Completed {
+			style = CompletedItemStyle
+		} else {
+			style = NormalItemStyle
+		}
+
+		line += style.Render(t.Name) + ProjectNamesStyle.Render(" ("+model.CategoryString(t.Category)+")")
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpDescStyle.Render(m.HintDependencies))
+
+	a.projectsModalViewport.SetContent(b.String())
+	return a.projectsModalViewport.View()
+}
+
 func (a *App) renderHelpModal() string {
 	m := i18n.Get()
 	var b strings.Builder
@@ -1477,6 +2049,7 @@ func (a *App) renderHelpModal() string {
 		{m.HelpKeyNavList, m.HelpNavList},
 		{m.HelpKeyNavTabs, m.HelpNavTabs},
 		{"P", m.HelpNavProjects},
+		{"S", m.HelpNavStats},
 		{"", ""},
 		{m.HelpTaskSection, ""},
 		{"a", m.HelpTaskNew},
@@ -1486,6 +2059,13 @@ func (a *App) renderHelpModal() string {
 		{m.HelpKeyComplete, m.HelpTaskComplete},
 		{"D", m.HelpTaskDeleteDone},
 		{"p", m.HelpTaskAssoc},
+		{"b", m.HelpTaskDeps},
+		{"t", m.HelpTaskTimer},
+		{"F", m.HelpTaskFocus},
+		{"", ""},
+		{m.HelpReviewSection, ""},
+		{"r", m.HelpReviewList},
+		{"R", m.HelpReviewAll},
 		{"", ""},
 		{m.HelpMoveSection, ""},
 		{"1", m.HelpMoveToday},
@@ -1507,6 +2087,9 @@ func (a *App) renderHelpModal() string {
 		{m.HelpGeneralSection, ""},
 		{"?", m.HelpGeneralHelp},
 		{"L", m.HelpGeneralLanguage},
+		{"W", m.HelpGeneralWorkspace},
+		{"B", m.HelpGeneralBackups},
+		{"M", m.HelpGeneralExport},
 		{m.HelpKeyQuit, m.HelpGeneralQuit},
 	}
 
@@ -1558,6 +2141,51 @@ func (a *App) renderConfirmDeleteModal() string {
 	return b.String()
 }
 
+// bodyHeight is the height left for the current screen, below the unsaved
+// changes banner while it's shown.
+func (a *App) bodyHeight() int {
+	if a.modal == ModalNone {
+		if banner := a.renderSaveBanner(); banner != "" {
+			return a.height - lipgloss.Height(banner)
+		}
+	}
+	return a.height
+}
+
+// renderSaveBanner warns about changes that only exist in memory because the
+// last save failed.
+func (a *App) renderSaveBanner() string {
+	if !a.store.Dirty() {
+		return ""
+	}
+	text := fmt.Sprintf(i18n.Get().BannerUnsaved, a.saveErr, int(saveRetryInterval.Seconds()))
+	return SaveBannerStyle.Width(a.width).MaxHeight(1).Render(text)
+}
+
+func (a *App) renderUnsavedQuitModal() string {
+	m := i18n.Get()
+	var b strings.Builder
+
+	b.WriteString(ModalTitleStyle.Render(m.ModalUnsavedQuit))
+	b.WriteString("\n\n")
+
+	b.WriteString(DetailValueStyle.Render(m.ConfirmUnsavedQuit))
+	b.WriteString("\n\n")
+	if a.saveErr != nil {
+		b.WriteString(StatusErrorStyle.Width(50).Render(a.saveErr.Error()))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(HelpKeyStyle.Render("R"))
+	b.WriteString(HelpDescStyle.Render(" " + m.UnsavedRetry + "   "))
+	b.WriteString(HelpKeyStyle.Render("Q"))
+	b.WriteString(HelpDescStyle.Render(" " + m.UnsavedDiscard + "   "))
+	b.WriteString(HelpKeyStyle.Render("Esc"))
+	b.WriteString(HelpDescStyle.Render(" " + m.ConfirmNo))
+
+	return b.String()
+}
+
 func (a *App) renderLanguageModal() string {
 	m := i18n.Get()
 	var b strings.Builder
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"t7t/internal/i18n"
+	"t7t/internal/keys"
+	"t7t/internal/model"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// backupRows is how many backups the picker shows at once.
+const backupRows = 12
+
+func (a *App) openBackupsModal() {
+	backups, err := a.store.Backups()
+	if err != nil {
+		a.statusMsg = fmt.Sprintf(i18n.Get().StatusBackupFailed, err)
+		a.statusErr = true
+		return
+	}
+	a.backups = backups
+	a.backupIndex = 0
+	a.backupConfirm = false
+	a.modal = ModalBackups
+}
+
+// handleBackupsInput drives the restore picker: choosing a backup first shows
+// how it compares to the current data, and only a second confirmation
+// restores it.
+func (a *App) handleBackupsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if len(a.backups) == 0 {
+		a.modal = ModalNone
+		return a, nil
+	}
+
+	if a.backupConfirm {
+		switch msg.String() {
+		case "y", "Y", "s", "S", "enter":
+			backup := a.backups[a.backupIndex]
+			a.modal = ModalNone
+			if a.persist(a.store.RestoreBackup(&backup)) {
+				a.statusMsg = fmt.Sprintf(i18n.Get().StatusBackupRestored, backup.Name)
+			}
+			a.taskIndex = 0
+			a.projectIndex = 0
+			a.taskListViewport.GotoTop()
+			a.taskDetailViewport.GotoTop()
+			a.projectListViewport.GotoTop()
+			return a, a.ensureTimerTick()
+		case "n", "N":
+			a.backupConfirm = false
+		}
+		return a, nil
+	}
+
+	switch {
+	case key.Matches(msg, keys.Keys.Down):
+		a.backupIndex = (a.backupIndex + 1) % len(a.backups)
+	case key.Matches(msg, keys.Keys.Up):
+		a.backupIndex = (a.backupIndex - 1 + len(a.backups)) % len(a.backups)
+	case key.Matches(msg, keys.Keys.Enter):
+		a.backupConfirm = true
+	}
+	return a, nil
+}
+
+func backupCounts(b model.Backup) string {
+	return fmt.Sprintf(i18n.Get().BackupCounts, b.Tasks, b.Open, b.Projects)
+}
+
+func (a *App) renderBackupsModal() string {
+	m := i18n.Get()
+	var b strings.Builder
+
+	b.WriteString(ModalTitleStyle.Render(m.ModalBackups))
+	b.WriteString("\n\n")
+
+	if len(a.backups) == 0 {
+		b.WriteString(NormalItemStyle.Width(50).Render(m.BackupsEmpty))
+		return b.String()
+	}
+
+	if a.backupConfirm {
+		backup := a.backups[a.backupIndex]
+		current := model.Backup{Tasks: len(a.store.Tasks), Projects: len(a.store.Projects)}
+		for _, t := range a.store.Tasks {
+			if !t.Completed {
+				current.Open++
+			}
+		}
+
+		b.WriteString(SelectedItemStyle.Render(backup.Time.Format("2006-01-02 15:04")))
+		b.WriteString("\n\n")
+		b.WriteString(DetailLabelStyle.Render(m.BackupCurrent))
+		b.WriteString(DetailValueStyle.Render(backupCounts(current)))
+		b.WriteString("\n")
+		b.WriteString(DetailLabelStyle.Render(m.BackupSelected))
+		b.WriteString(DetailValueStyle.Render(backupCounts(backup)))
+		b.WriteString("\n\n")
+		b.WriteString(DetailValueStyle.Width(50).Render(m.BackupConfirm))
+		b.WriteString("\n\n")
+
+		b.WriteString(HelpKeyStyle.Render("S/Enter"))
+		b.WriteString(HelpDescStyle.Render(" " + m.ConfirmYes + "   "))
+		b.WriteString(HelpKeyStyle.Render("N/Esc"))
+		b.WriteString(HelpDescStyle.Render(" " + m.ConfirmNo))
+		return b.String()
+	}
+
+	start := 0
+	if a.backupIndex >= backupRows {
+		start = a.backupIndex - backupRows + 1
+	}
+	end := min(start+backupRows, len(a.backups))
+	for i := start; i < end; i++ {
+		backup := a.backups[i]
+		style := NormalItemStyle
+		prefix := "  "
+		if i == a.backupIndex {
+			style = SelectedItemStyle
+			prefix = "> "
+		}
+		b.WriteString(style.Render(prefix + backup.Time.Format("2006-01-02 15:04")))
+		b.WriteString(HelpDescStyle.Render("  " + backupCounts(backup)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpDescStyle.Render(m.BackupsHint))
+
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"t7t/internal/daemon"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type daemonChangeMsg struct {
+	client  *daemon.Client
+	version uint64
+	err     error
+}
+
+// connectDaemon makes the TUI a client of the daemon serving the current
+// store, if one is running: saves go through the daemon, which writes the
+// file for everyone, and its change notifications make changes made through
+// it show up at once. Without a daemon, the store writes the file itself and
+// the regular polling of the data file picks up other changes.
+func (a *App) connectDaemon() tea.Cmd {
+	if a.daemon != nil {
+		a.daemon.Close()
+		a.daemon = nil
+	}
+	a.store.SaveVia(nil)
+	client, err := daemon.Dial(a.store.Dir())
+	if err != nil {
+		return nil
+	}
+	a.daemon, a.daemonVersion = client, 0
+	a.store.SaveVia(client.Save)
+	return a.watchDaemon()
+}
+
+// watchDaemon waits in the background for the daemon's next change.
+func (a *App) watchDaemon() tea.Cmd {
+	client, since := a.daemon, a.daemonVersion
+	return func() tea.Msg {
+		version, err := client.Wait(since)
+		return daemonChangeMsg{client: client, version: version, err: err}
+	}
+}
+
+func (a *App) handleDaemonChange(msg daemonChangeMsg) tea.Cmd {
+	// Answers for the connection of a workspace switched away from.
+	if msg.client != a.daemon {
+		return nil
+	}
+	if msg.err != nil {
+		a.daemon.Close()
+		a.daemon = nil
+		a.store.SaveVia(nil)
+		return nil
+	}
+
+	var reload tea.Cmd
+	if msg.version != a.daemonVersion {
+		a.daemonVersion = msg.version
+		reload = a.reloadStore()
+	}
+	return tea.Batch(reload, a.watchDaemon())
+}
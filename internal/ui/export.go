@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"t7t/internal/i18n"
+	"t7t/internal/markdown"
+)
+
+// exportFile is where the export action writes, inside the data directory.
+const exportFile = "export.md"
+
+// exportMarkdown writes the lists as a markdown checklist, grouped by project
+// when the projects are on screen. Encrypted stores are never written out in
+// plain text from a single key press.
+func (a *App) exportMarkdown() {
+	m := i18n.Get()
+
+	if a.store.Encrypted() {
+		a.statusMsg = m.StatusExportEncrypted
+		a.statusErr = true
+		return
+	}
+
+	by := markdown.ByCategory
+	if a.viewMode == ViewProjects {
+		by = markdown.ByProject
+	}
+
+	path := filepath.Join(a.store.Dir(), exportFile)
+	err := writeExport(path, func(f *os.File) error {
+		return markdown.Export(a.store, f, by)
+	})
+	if err != nil {
+		a.statusMsg = fmt.Sprintf(m.StatusExportFailed, err)
+		a.statusErr = true
+		return
+	}
+	a.statusMsg = fmt.Sprintf(m.StatusExported, path)
+}
+
+func writeExport(path string, write func(*os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,283 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"t7t/internal/i18n"
+	"t7t/internal/keys"
+	"t7t/internal/model"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type pomodoroPhase int
+
+const (
+	phaseWork pomodoroPhase = iota
+	phaseShortBreak
+	phaseLongBreak
+)
+
+type pomodoroTickMsg time.Time
+
+func tickPomodoro() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return pomodoroTickMsg(t)
+	})
+}
+
+// focusSession is the Pomodoro timer for a single task. While running, the
+// remaining time is derived from endsAt so ticks arriving late don't drift.
+type focusSession struct {
+	taskID    string
+	phase     pomodoroPhase
+	remaining time.Duration
+	endsAt    time.Time
+	running   bool
+	completed int
+	ticking   bool
+}
+
+func phaseDuration(settings model.PomodoroSettings, phase pomodoroPhase) time.Duration {
+	switch phase {
+	case phaseShortBreak:
+		return time.Duration(settings.ShortBreakMinutes) * time.Minute
+	case phaseLongBreak:
+		return time.Duration(settings.LongBreakMinutes) * time.Minute
+	default:
+		return time.Duration(settings.WorkMinutes) * time.Minute
+	}
+}
+
+func phaseName(phase pomodoroPhase) string {
+	m := i18n.Get()
+	switch phase {
+	case phaseShortBreak:
+		return m.FocusPhaseShortBreak
+	case phaseLongBreak:
+		return m.FocusPhaseLongBreak
+	default:
+		return m.FocusPhaseWork
+	}
+}
+
+func (a *App) startFocus(task *model.Task) (tea.Model, tea.Cmd) {
+	a.focus = focusSession{
+		taskID:    task.ID,
+		phase:     phaseWork,
+		remaining: phaseDuration(a.store.PomodoroSettings(), phaseWork),
+		ticking:   a.focus.ticking,
+	}
+	a.viewMode = ViewFocus
+	a.detailFocused = false
+	return a, a.toggleFocusTimer()
+}
+
+func (a *App) toggleFocusTimer() tea.Cmd {
+	if a.focus.running {
+		a.focus.remaining = time.Until(a.focus.endsAt)
+		a.focus.running = false
+		return nil
+	}
+
+	a.focus.endsAt = time.Now().Add(a.focus.remaining)
+	a.focus.running = true
+	if a.focus.ticking {
+		return nil
+	}
+	a.focus.ticking = true
+	return tickPomodoro()
+}
+
+func (a *App) setFocusPhase(phase pomodoroPhase) {
+	a.focus.phase = phase
+	a.focus.remaining = phaseDuration(a.store.PomodoroSettings(), phase)
+	a.focus.running = false
+}
+
+// finishFocusPhase records a completed work session on the task and moves on
+// to the following phase, paused until the user starts it.
+func (a *App) finishFocusPhase() tea.Cmd {
+	m := i18n.Get()
+	settings := a.store.PomodoroSettings()
+
+	if a.focus.phase != phaseWork {
+		a.setFocusPhase(phaseWork)
+		a.statusMsg = m.StatusBreakDone
+		return nil
+	}
+
+	if task := a.store.GetTask(a.focus.taskID); task != nil {
+		a.persist(a.store.RecordPomodoro(task))
+	}
+	a.focus.completed++
+	a.statusMsg = m.StatusPomodoroDone
+
+	if a.focus.completed%settings.LongBreakEvery == 0 {
+		a.setFocusPhase(phaseLongBreak)
+	} else {
+		a.setFocusPhase(phaseShortBreak)
+	}
+
+	if settings.Celebrate {
+		return a.startConfetti()
+	}
+	return nil
+}
+
+func (a *App) updatePomodoro() tea.Cmd {
+	if a.viewMode != ViewFocus || !a.focus.running {
+		a.focus.ticking = false
+		return nil
+	}
+
+	a.focus.remaining = time.Until(a.focus.endsAt)
+	if a.focus.remaining > 0 {
+		return tickPomodoro()
+	}
+
+	a.focus.ticking = false
+	return a.finishFocusPhase()
+}
+
+func (a *App) adjustFocusDuration(delta int) {
+	settings := a.store.PomodoroSettings()
+	target := &settings.WorkMinutes
+	switch a.focus.phase {
+	case phaseShortBreak:
+		target = &settings.ShortBreakMinutes
+	case phaseLongBreak:
+		target = &settings.LongBreakMinutes
+	}
+	if *target+delta < 1 {
+		return
+	}
+	*target += delta
+	a.persist(a.store.SetPomodoroSettings(settings))
+
+	if a.focus.running {
+		a.focus.endsAt = a.focus.endsAt.Add(time.Duration(delta) * time.Minute)
+		a.focus.remaining = time.Until(a.focus.endsAt)
+	} else {
+		a.focus.remaining += time.Duration(delta) * time.Minute
+		if a.focus.remaining < 0 {
+			a.focus.remaining = 0
+		}
+	}
+}
+
+func (a *App) handleFocusInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Keys.Quit):
+		return a.quit()
+	case key.Matches(msg, keys.Keys.Escape):
+		a.focus.running = false
+		a.viewMode = ViewTasks
+		return a, nil
+	}
+
+	switch msg.String() {
+	case " ":
+		return a, a.toggleFocusTimer()
+	case "s":
+		if a.focus.phase == phaseWork {
+			a.setFocusPhase(phaseShortBreak)
+		} else {
+			a.setFocusPhase(phaseWork)
+		}
+	case "r":
+		a.setFocusPhase(a.focus.phase)
+	case "+", "=":
+		a.adjustFocusDuration(1)
+	case "-":
+		a.adjustFocusDuration(-1)
+	case "c":
+		settings := a.store.PomodoroSettings()
+		settings.Celebrate = !settings.Celebrate
+		a.persist(a.store.SetPomodoroSettings(settings))
+	}
+	return a, nil
+}
+
+func (a *App) viewFocus() string {
+	m := i18n.Get()
+	settings := a.store.PomodoroSettings()
+
+	logo := LogoStyle.Render(LogoArt)
+	header := ActiveTabStyle.Render(m.FocusTitle)
+
+	statusHeight := 2
+	gaps := 2
+	panelBorderPadding := 4
+	contentHeight := a.bodyHeight() - lipgloss.Height(logo) - lipgloss.Height(header) - gaps - statusHeight - panelBorderPadding
+	if contentHeight < 5 {
+		contentHeight = 5
+	}
+	width := a.width - 4
+
+	var b strings.Builder
+	task := a.store.GetTask(a.focus.taskID)
+	if task != nil {
+		b.WriteString(DetailTitleStyle.Render(task.Name))
+		b.WriteString("\n\n")
+	}
+
+	phase := phaseName(a.focus.phase)
+	if !a.focus.running {
+		phase += " " + m.FocusPaused
+	}
+	b.WriteString(DetailLabelStyle.Render(phase))
+	b.WriteString("\n\n")
+
+	remaining := a.focus.remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+	remaining = remaining.Round(time.Second)
+	clock := fmt.Sprintf("%02d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+	b.WriteString(FocusClockStyle.Render(clock))
+	b.WriteString("\n\n")
+
+	total := phaseDuration(settings, a.focus.phase)
+	elapsed := int((total - remaining) / time.Second)
+	b.WriteString(progressBar(elapsed, int(total/time.Second), 40))
+	b.WriteString("\n\n")
+
+	if task != nil {
+		b.WriteString(DetailValueStyle.Render(fmt.Sprintf(m.FocusPomodoros, task.Pomodoros)))
+		b.WriteString("\n")
+	}
+	b.WriteString(DetailValueStyle.Render(fmt.Sprintf(m.FocusSession, a.focus.completed)))
+	b.WriteString("\n\n")
+
+	b.WriteString(HelpDescStyle.Render(fmt.Sprintf(m.FocusSettings,
+		settings.WorkMinutes, settings.ShortBreakMinutes, settings.LongBreakMinutes, settings.LongBreakEvery)))
+	b.WriteString("\n")
+	celebrate := m.FocusNo
+	if settings.Celebrate {
+		celebrate = m.FocusYes
+	}
+	b.WriteString(HelpDescStyle.Render(fmt.Sprintf(m.FocusCelebrate, celebrate)))
+
+	panel := ActivePanelStyle.Width(width).Height(contentHeight).Render(b.String())
+
+	var parts []string
+	if a.statusMsg != "" {
+		parts = append(parts, StatusMessageStyle.Render(a.statusMsg))
+	}
+	parts = append(parts, HelpDescStyle.Render(m.FocusHint))
+	statusBar := StatusBarStyle.Render(strings.Join(parts, " | "))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		logo,
+		"",
+		header,
+		"",
+		panel,
+		statusBar,
+	)
+}
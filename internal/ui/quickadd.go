@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"t7t/internal/i18n"
+	"t7t/internal/model"
+)
+
+// addQuickTask creates the task typed in the new task form, reading the name
+// as quick-add syntax (!week, +project, due:fri) on top of the projects
+// checked in the form. Projects named in the name that don't exist yet are
+// created along with the task, in the same save. It returns false, keeping
+// the form open, when the name holds nothing but quick-add tokens.
+func (a *App) addQuickTask(projectIDs []string) bool {
+	m := i18n.Get()
+
+	q := model.ParseQuickAdd(a.nameInput.Value(), time.Now())
+	if q.Name == "" {
+		if strings.TrimSpace(a.nameInput.Value()) == "" {
+			return true
+		}
+		a.statusMsg = m.StatusTaskNameMissing
+		a.statusErr = true
+		return false
+	}
+	batch := &model.Batch{}
+	task := q.Task(a.store, batch, a.categories[a.activeTab])
+	task.Description = a.descInput.Value()
+	for _, id := range projectIDs {
+		if !task.HasProject(id) {
+			task.ProjectIDs = append(task.ProjectIDs, id)
+		}
+	}
+	batch.Tasks = append(batch.Tasks, task)
+	if !a.persist(a.store.AddBatch(batch)) {
+		return true
+	}
+	a.statusMsg = m.StatusTaskCreated
+
+	// Follow the task to its list when the name picked another one.
+	if i := slices.Index(a.categories, task.Category); i >= 0 && i != a.activeTab {
+		a.activeTab = i
+		a.taskIndex = 0
+		a.detailFocused = false
+		a.taskDetailViewport.GotoTop()
+	}
+	return true
+}
+
+// quickAddPreview shows how the name typed in the new task form will be
+// read, or a reminder of the syntax while there's nothing to read.
+func (a *App) quickAddPreview() string {
+	m := i18n.Get()
+
+	q := model.ParseQuickAdd(a.nameInput.Value(), time.Now())
+	if q.Category == "" && len(q.Projects) == 0 && q.Due == nil {
+		return HelpDescStyle.Render(m.FormQuickAddHint)
+	}
+
+	category := q.Category
+	if category == "" {
+		category = a.categories[a.activeTab]
+	}
+	parts := []string{q.Name, category.String()}
+	for _, name := range q.ProjectNames(a.store) {
+		part := "+" + name
+		if a.store.ProjectByName(name) == nil {
+			part += " " + m.FormQuickAddNewProject
+		}
+		parts = append(parts, part)
+	}
+	if q.Due != nil {
+		parts = append(parts, m.LabelDue+q.Due.Format("2006-01-02"))
+	}
+	return HelpDescStyle.Render("→ " + strings.Join(parts, " · "))
+}
@@ -0,0 +1,296 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"t7t/internal/i18n"
+	"t7t/internal/keys"
+	"t7t/internal/model"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type reviewAction int
+
+const (
+	reviewKept reviewAction = iota
+	reviewMoved
+	reviewCompleted
+	reviewDeferred
+	reviewDeleted
+)
+
+type reviewChange struct {
+	name   string
+	action reviewAction
+	detail string
+}
+
+// reviewSession walks through a fixed list of open tasks one by one,
+// remembering what happened to each of them for the final summary.
+type reviewSession struct {
+	categories []model.Category
+	taskIDs    []string
+	index      int
+	counts     map[reviewAction]int
+	changes    []reviewChange
+	finished   bool
+}
+
+func (r *reviewSession) current(store *model.Store) *model.Task {
+	for r.index < len(r.taskIDs) {
+		if task := store.GetTask(r.taskIDs[r.index]); task != nil {
+			return task
+		}
+		r.index++
+	}
+	r.finished = true
+	return nil
+}
+
+func (r *reviewSession) record(action reviewAction, name, detail string) {
+	r.counts[action]++
+	if action != reviewKept {
+		r.changes = append(r.changes, reviewChange{name: name, action: action, detail: detail})
+	}
+	r.index++
+	if r.index >= len(r.taskIDs) {
+		r.finished = true
+	}
+}
+
+func (r *reviewSession) reviewed() int {
+	var total int
+	for _, n := range r.counts {
+		total += n
+	}
+	return total
+}
+
+func (r *reviewSession) title() string {
+	m := i18n.Get()
+	if len(r.categories) == 1 {
+		return fmt.Sprintf(m.ReviewTitleList, model.CategoryString(r.categories[0]))
+	}
+	return m.ReviewTitleAll
+}
+
+// deferCategory returns the next less urgent category. The general list is
+// already the last stop, so tasks there stay where they are.
+func deferCategory(c model.Category) model.Category {
+	switch c {
+	case model.CategoryToday:
+		return model.CategoryWeek
+	case model.CategoryWeek:
+		return model.CategoryNotUrgent
+	default:
+		return model.CategoryGeneral
+	}
+}
+
+func (a *App) startReview(categories []model.Category) (tea.Model, tea.Cmd) {
+	var ids []string
+	for _, c := range categories {
+		for _, t := range a.store.GetTasksByCategory(c) {
+			if !t.Completed {
+				ids = append(ids, t.ID)
+			}
+		}
+	}
+
+	if len(ids) == 0 {
+		a.statusMsg = i18n.Get().ReviewEmpty
+		return a, nil
+	}
+
+	a.review = reviewSession{
+		categories: categories,
+		taskIDs:    ids,
+		counts:     make(map[reviewAction]int),
+	}
+	a.viewMode = ViewReview
+	a.detailFocused = false
+	a.statusMsg = ""
+	return a, nil
+}
+
+func (a *App) finishReview() {
+	a.viewMode = ViewTasks
+	a.taskIndex = 0
+	a.taskListViewport.GotoTop()
+	a.taskDetailViewport.GotoTop()
+	a.statusMsg = i18n.Get().ReviewFinished
+}
+
+func (a *App) handleReviewInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, keys.Keys.Quit) {
+		return a.quit()
+	}
+
+	if a.review.finished {
+		a.finishReview()
+		return a, nil
+	}
+
+	task := a.review.current(a.store)
+	if task == nil {
+		return a, nil
+	}
+
+	switch keyStr := msg.String(); {
+	case key.Matches(msg, keys.Keys.Escape):
+		a.review.finished = true
+
+	case keyStr == "enter" || keyStr == "k":
+		a.review.record(reviewKept, task.Name, "")
+
+	case key.Matches(msg, keys.Keys.MoveToday):
+		a.reviewMove(task, model.CategoryToday, reviewMoved)
+	case key.Matches(msg, keys.Keys.MoveWeek):
+		a.reviewMove(task, model.CategoryWeek, reviewMoved)
+	case key.Matches(msg, keys.Keys.MoveNotUrgent):
+		a.reviewMove(task, model.CategoryNotUrgent, reviewMoved)
+	case key.Matches(msg, keys.Keys.MoveGeneral):
+		a.reviewMove(task, model.CategoryGeneral, reviewMoved)
+
+	case keyStr == "f":
+		a.reviewMove(task, deferCategory(task.Category), reviewDeferred)
+
+	case key.Matches(msg, keys.Keys.CompleteTask):
+		task.ToggleComplete()
+		if a.persist(a.store.UpdateTask(task)) {
+			a.statusMsg = a.unblockedNotice(task)
+		}
+		a.review.record(reviewCompleted, task.Name, "")
+
+	case key.Matches(msg, keys.Keys.DeleteTask):
+		a.modal = ModalConfirmDelete
+		a.deleteType = "task"
+		a.deleteID = task.ID
+		a.deleteName = task.Name
+	}
+
+	return a, nil
+}
+
+func (a *App) reviewMove(task *model.Task, category model.Category, action reviewAction) {
+	if task.Category == category {
+		a.review.record(reviewKept, task.Name, "")
+		return
+	}
+	task.SetCategory(category)
+	a.persist(a.store.UpdateTask(task))
+	a.review.record(action, task.Name, model.CategoryString(category))
+}
+
+func (a *App) viewReview() string {
+	m := i18n.Get()
+
+	logo := LogoStyle.Render(LogoArt)
+
+	headerParts := []string{ActiveTabStyle.Render(a.review.title())}
+	if !a.review.finished {
+		progress := fmt.Sprintf(m.ReviewProgress, a.review.index+1, len(a.review.taskIDs))
+		headerParts = append(headerParts, TabGapStyle.Render(" | "), InactiveTabStyle.Render(progress))
+	}
+	header := lipgloss.JoinHorizontal(lipgloss.Center, headerParts...)
+
+	statusHeight := 2
+	gaps := 2
+	panelBorderPadding := 4
+	contentHeight := a.bodyHeight() - lipgloss.Height(logo) - lipgloss.Height(header) - gaps - statusHeight - panelBorderPadding
+	if contentHeight < 5 {
+		contentHeight = 5
+	}
+	width := a.width - 4
+
+	var body, hint string
+	task := a.review.current(a.store)
+	if a.review.finished || task == nil {
+		body = a.renderReviewSummary()
+		hint = m.ReviewSummaryHint
+	} else {
+		body = DetailLabelStyle.Render(m.ReviewLabelList) +
+			DetailValueStyle.Render(model.CategoryString(task.Category)) + "\n\n" +
+			a.renderTaskDetailContent(task, width)
+		hint = m.ReviewHint
+	}
+
+	vp := viewport.New(width, contentHeight)
+	vp.SetContent(body)
+	panel := ActivePanelStyle.Width(width).Height(contentHeight).Render(vp.View())
+
+	var parts []string
+	if a.statusMsg != "" {
+		parts = append(parts, StatusMessageStyle.Render(a.statusMsg))
+	}
+	parts = append(parts, HelpDescStyle.Render(hint))
+	statusBar := StatusBarStyle.Render(strings.Join(parts, " | "))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		logo,
+		"",
+		header,
+		"",
+		panel,
+		statusBar,
+	)
+}
+
+func (a *App) renderReviewSummary() string {
+	m := i18n.Get()
+	r := a.review
+	var b strings.Builder
+
+	b.WriteString(DetailTitleStyle.Render(m.ReviewSummaryTitle))
+	b.WriteString("\n\n")
+
+	b.WriteString(DetailValueStyle.Render(fmt.Sprintf(m.ReviewSummaryReviewed, r.reviewed(), len(r.taskIDs))))
+	b.WriteString("\n\n")
+
+	counts := []struct {
+		format string
+		action reviewAction
+	}{
+		{m.ReviewSummaryKept, reviewKept},
+		{m.ReviewSummaryMoved, reviewMoved},
+		{m.ReviewSummaryCompleted, reviewCompleted},
+		{m.ReviewSummaryDeferred, reviewDeferred},
+		{m.ReviewSummaryDeleted, reviewDeleted},
+	}
+	for _, c := range counts {
+		b.WriteString(DetailValueStyle.Render(fmt.Sprintf(c.format, r.counts[c.action])))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(DetailLabelStyle.Render(m.ReviewSummaryChanges))
+	b.WriteString("\n")
+	if len(r.changes) == 0 {
+		b.WriteString(DetailValueStyle.Render(m.ReviewSummaryNoChanges))
+		return b.String()
+	}
+
+	var lines []string
+	for _, c := range r.changes {
+		var action string
+		switch c.action {
+		case reviewMoved:
+			action = fmt.Sprintf(m.ReviewActionMoved, c.detail)
+		case reviewDeferred:
+			action = fmt.Sprintf(m.ReviewActionDeferred, c.detail)
+		case reviewCompleted:
+			action = m.ReviewActionCompleted
+		case reviewDeleted:
+			action = m.ReviewActionDeleted
+		}
+		lines = append(lines, "- "+c.name+": "+action)
+	}
+	b.WriteString(DetailValueStyle.Render(strings.Join(lines, "\n")))
+
+	return b.String()
+}
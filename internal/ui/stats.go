@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"t7t/internal/i18n"
+	"t7t/internal/keys"
+	"t7t/internal/model"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var sparkChars = []rune("▁▂▃▄▅▆▇█")
+
+func (a *App) handleStatsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Keys.Escape):
+		a.viewMode = ViewTasks
+	case key.Matches(msg, keys.Keys.Down):
+		a.statsViewport.LineDown(1)
+	case key.Matches(msg, keys.Keys.Up):
+		a.statsViewport.LineUp(1)
+	}
+	return a, nil
+}
+
+func (a *App) viewStats() string {
+	m := i18n.Get()
+
+	logo := LogoStyle.Render(LogoArt)
+	tab := InactiveTabStyle.Render(m.StatsBackToTasks)
+
+	statusHeight := 2
+	gaps := 2
+	panelBorderPadding := 4
+	contentHeight := a.bodyHeight() - lipgloss.Height(logo) - lipgloss.Height(tab) - gaps - statusHeight - panelBorderPadding
+	if contentHeight < 5 {
+		contentHeight = 5
+	}
+	width := a.width - 4
+
+	a.statsViewport.Width = width
+	a.statsViewport.Height = contentHeight
+	a.statsViewport.SetContent(a.renderStats(a.store.ComputeStats(time.Now()), width))
+	panel := ListPanelStyle.Width(width).Height(contentHeight).Render(a.statsViewport.View())
+
+	helpText := HelpKeyStyle.Render("j/k") + HelpDescStyle.Render(":"+m.HelpScroll+" ") +
+		HelpKeyStyle.Render("S") + HelpDescStyle.Render(":"+m.HelpBack+" ") +
+		HelpKeyStyle.Render("q") + HelpDescStyle.Render(":"+m.HelpQuit)
+	statusBar := StatusBarStyle.Render(helpText)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		logo,
+		"",
+		tab,
+		"",
+		panel,
+		statusBar,
+	)
+}
+
+func (a *App) renderStats(stats model.Stats, width int) string {
+	m := i18n.Get()
+	var b strings.Builder
+
+	b.WriteString(DetailTitleStyle.Render(m.StatsTitle))
+	b.WriteString("\n\n")
+
+	// Daily throughput sparkline
+	b.WriteString(DetailLabelStyle.Render(fmt.Sprintf(m.StatsThroughput, len(stats.CompletedPerDay))))
+	b.WriteString("\n")
+	b.WriteString(HelpKeyStyle.Render(sparkline(stats.CompletedPerDay)))
+	b.WriteString("  ")
+	b.WriteString(DetailValueStyle.Render(strings.Join([]string{
+		fmt.Sprintf(m.StatsToday, stats.CompletedToday),
+		fmt.Sprintf(m.StatsThisWeek, stats.CompletedWeek),
+		fmt.Sprintf(m.StatsLastWeek, stats.CompletedLastWeek),
+	}, "  ")))
+	b.WriteString("\n\n")
+
+	// Weekly bar chart
+	b.WriteString(DetailLabelStyle.Render(m.StatsWeekly))
+	b.WriteString("\n")
+	maxCount := 0
+	for _, w := range stats.CompletedPerWeek {
+		if w.Count > maxCount {
+			maxCount = w.Count
+		}
+	}
+	barWidth := width - 20
+	if barWidth > 40 {
+		barWidth = 40
+	}
+	if barWidth < 5 {
+		barWidth = 5
+	}
+	for _, w := range stats.CompletedPerWeek {
+		bar := 0
+		if maxCount > 0 {
+			bar = w.Count * barWidth / maxCount
+		}
+		b.WriteString(HelpDescStyle.Render(w.Day.Format(m.StatsDateFormat) + " "))
+		b.WriteString(HelpKeyStyle.Render(strings.Repeat("█", bar)))
+		b.WriteString(DetailValueStyle.Render(fmt.Sprintf(" %d", w.Count)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Open tasks and their average age
+	b.WriteString(DetailLabelStyle.Render(m.StatsOpenAge))
+	b.WriteString("\n")
+	for _, c := range stats.OpenByCategory {
+		b.WriteString(HelpDescStyle.Render(fmt.Sprintf("%-14s", model.CategoryString(c.Category))))
+		b.WriteString(DetailValueStyle.Render(fmt.Sprintf(m.StatsOpenAgeLine, c.Open, formatAge(c.AverageAge))))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Project progress
+	b.WriteString(DetailLabelStyle.Render(m.StatsProjects))
+	b.WriteString("\n")
+	if len(stats.Projects) == 0 {
+		b.WriteString(DetailValueStyle.Render(m.StatsNoProjects))
+	}
+	for _, p := range stats.Projects {
+		style := NormalItemStyle
+		if p.Project.Completed {
+			style = CompletedItemStyle
+		}
+		b.WriteString(style.Render(p.Project.Name))
+		b.WriteString("\n  ")
+		b.WriteString(progressBar(p.Done, p.Total, barWidth))
+		percent := 0
+		if p.Total > 0 {
+			percent = p.Done * 100 / p.Total
+		}
+		b.WriteString(DetailValueStyle.Render(fmt.Sprintf(" %d/%d (%d%%)", p.Done, p.Total, percent)))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func sparkline(days []model.DayCount) string {
+	maxCount := 0
+	for _, d := range days {
+		if d.Count > maxCount {
+			maxCount = d.Count
+		}
+	}
+
+	var b strings.Builder
+	for _, d := range days {
+		idx := 0
+		if maxCount > 0 {
+			idx = d.Count * (len(sparkChars) - 1) / maxCount
+		}
+		b.WriteRune(sparkChars[idx])
+	}
+	return b.String()
+}
+
+func progressBar(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = done * width / total
+	}
+	if filled < 0 {
+		filled = 0
+	} else if filled > width {
+		filled = width
+	}
+	return HelpKeyStyle.Render(strings.Repeat("█", filled)) +
+		HelpDescStyle.Render(strings.Repeat("░", width-filled))
+}
+
+func formatAge(d time.Duration) string {
+	m := i18n.Get()
+	if d >= 24*time.Hour {
+		return fmt.Sprintf(m.StatsDays, int(d.Hours()/24))
+	}
+	return fmt.Sprintf(m.StatsHours, int(d.Hours()))
+}
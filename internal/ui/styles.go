@@ -94,6 +94,24 @@ var (
 	StatusErrorStyle = lipgloss.NewStyle().
 				Foreground(errorColor)
 
+	SaveBannerStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(errorColor).
+			Bold(true).
+			Padding(0, 1)
+
+	TimerStyle = lipgloss.NewStyle().
+			Foreground(warningColor).
+			Bold(true)
+
+	// Focus mode clock
+	FocusClockStyle = lipgloss.NewStyle().
+			Foreground(highlightColor).
+			Bold(true).
+			Padding(0, 2).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(primaryColor)
+
 	// Help
 	HelpKeyStyle = lipgloss.NewStyle().
 			Foreground(primaryColor).
@@ -157,6 +175,12 @@ var (
 	ContextStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FF79C6")) // Pink/Magenta
 
+	// Blocked tasks
+	BlockedMarker = "⊘ "
+
+	BlockedStyle = lipgloss.NewStyle().
+			Foreground(errorColor)
+
 	// Category badges
 	CategoryTodayStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#FFFFFF")).
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"t7t/internal/config"
+	"t7t/internal/i18n"
+	"t7t/internal/keys"
+	"t7t/internal/model"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (a *App) openWorkspaceModal() {
+	workspaces, err := config.Workspaces()
+	if err != nil {
+		a.statusMsg = fmt.Sprintf(i18n.Get().StatusWorkspaceFailed, err)
+		a.statusErr = true
+		return
+	}
+	a.workspaces = workspaces
+	a.workspaceIndex = 0
+	for i, name := range workspaces {
+		if name == a.workspace {
+			a.workspaceIndex = i
+		}
+	}
+	a.modal = ModalWorkspace
+}
+
+// switchWorkspace opens the named workspace's store in place of the current
+// one. Changes that couldn't be saved yet would be lost, so it refuses to
+// switch while the store is dirty.
+func (a *App) switchWorkspace(name string) tea.Cmd {
+	m := i18n.Get()
+
+	if name == a.workspace {
+		return nil
+	}
+	if a.store.Dirty() {
+		a.statusMsg = m.StatusWorkspaceUnsaved
+		a.statusErr = true
+		return nil
+	}
+
+	dir, err := config.WorkspaceDir(name)
+	if err == nil {
+		var store *model.Store
+		if store, err = model.NewStore(dir); err == nil && store.Locked() {
+			// The passphrase prompt only runs before the TUI starts.
+			a.statusMsg = fmt.Sprintf(m.StatusWorkspaceLocked, name)
+			a.statusErr = true
+			return nil
+		}
+		if err == nil {
+			a.store = store
+		}
+	}
+	if err != nil {
+		a.statusMsg = fmt.Sprintf(m.StatusWorkspaceFailed, err)
+		a.statusErr = true
+		return nil
+	}
+
+	a.workspace = name
+	a.saveErr = nil
+	a.viewMode = ViewTasks
+	a.activeTab = 0
+	a.taskIndex = 0
+	a.projectIndex = 0
+	a.detailFocused = false
+	a.taskListViewport.GotoTop()
+	a.taskDetailViewport.GotoTop()
+	a.projectListViewport.GotoTop()
+	a.statusMsg = fmt.Sprintf(m.StatusWorkspaceSwitched, name)
+	return tea.Batch(a.ensureTimerTick(), a.connectDaemon())
+}
+
+func (a *App) handleWorkspaceInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.modal == ModalNewWorkspace {
+		if key.Matches(msg, keys.Keys.Enter) {
+			name := strings.TrimSpace(a.nameInput.Value())
+			if !config.ValidWorkspace(name) {
+				a.statusMsg = fmt.Sprintf(i18n.Get().StatusWorkspaceInvalid, name)
+				a.statusErr = true
+				return a, nil
+			}
+			a.modal = ModalNone
+			a.nameInput.Blur()
+			return a, a.switchWorkspace(name)
+		}
+		var cmd tea.Cmd
+		a.nameInput, cmd = a.nameInput.Update(msg)
+		return a, cmd
+	}
+
+	switch {
+	case key.Matches(msg, keys.Keys.Down):
+		a.workspaceIndex = (a.workspaceIndex + 1) % len(a.workspaces)
+	case key.Matches(msg, keys.Keys.Up):
+		a.workspaceIndex = (a.workspaceIndex - 1 + len(a.workspaces)) % len(a.workspaces)
+	case key.Matches(msg, keys.Keys.Enter):
+		a.modal = ModalNone
+		return a, a.switchWorkspace(a.workspaces[a.workspaceIndex])
+	case msg.String() == "a":
+		a.modal = ModalNewWorkspace
+		a.nameInput.Reset()
+		a.nameInput.Focus()
+		return a, textinput.Blink
+	}
+	return a, nil
+}
+
+func (a *App) renderWorkspaceModal() string {
+	m := i18n.Get()
+	var b strings.Builder
+
+	b.WriteString(ModalTitleStyle.Render(m.ModalWorkspace))
+	b.WriteString("\n\n")
+
+	for i, name := range a.workspaces {
+		line := CheckboxNormal
+		style := NormalItemStyle
+		if i == a.workspaceIndex {
+			line = CheckboxSelected
+			style = SelectedItemStyle
+		}
+		if name == a.workspace {
+			line += CheckboxChecked
+		} else {
+			line += CheckboxEmpty
+		}
+		b.WriteString(line + style.Render(name) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpDescStyle.Render(m.WorkspaceHint))
+
+	return b.String()
+}
+
+func (a *App) renderNewWorkspaceForm() string {
+	m := i18n.Get()
+	var b strings.Builder
+
+	b.WriteString(ModalTitleStyle.Render(m.ModalNewWorkspace))
+	b.WriteString("\n\n")
+
+	b.WriteString(InputLabelStyle.Render(m.FormWorkspaceName))
+	b.WriteString("\n")
+	b.WriteString(a.nameInput.View())
+	b.WriteString("\n\n")
+
+	b.WriteString(HelpDescStyle.Render(m.HintProjectForm))
+
+	return b.String()
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 
+	"t7t/internal/cli"
+	"t7t/internal/config"
 	"t7t/internal/i18n"
+	"t7t/internal/ical"
 	"t7t/internal/model"
 	"t7t/internal/ui"
 
@@ -12,13 +17,61 @@ import (
 )
 
 func main() {
-	store, err := model.NewStore()
+	flags := flag.NewFlagSet("t7t", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, i18n.Get().CLIUsage)
+	}
+	dataDir := flags.String("data-dir", "", "")
+	workspace := flags.String("workspace", config.DefaultWorkspace, "")
+	flags.StringVar(workspace, "w", config.DefaultWorkspace, "")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
+
+	if *dataDir != "" {
+		config.SetDataDir(*dataDir)
+		i18n.LoadSavedLanguage()
+	}
+
+	model.OnSave(ical.UpdateFeed)
+
+	dir, err := config.WorkspaceDir(*workspace)
+	if errors.Is(err, config.ErrInvalidWorkspace) {
+		fmt.Fprintf(os.Stderr, i18n.Get().ErrorInvalidWorkspace, *workspace)
+		os.Exit(1)
+	}
+	var store *model.Store
+	if err == nil {
+		store, err = model.NewStore(dir)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, i18n.Get().ErrorInitStorage, err)
 		os.Exit(1)
 	}
 
-	app := ui.NewApp(store)
+	if store.Locked() {
+		if err := cli.Unlock(store); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.Get().ErrorInitStorage, err)
+			os.Exit(1)
+		}
+	}
+
+	if args := flags.Args(); len(args) > 0 {
+		err := cli.Run(store, args)
+		if hookErr := store.HookError(); hookErr != nil {
+			fmt.Fprintf(os.Stderr, i18n.Get().WarningSaveHook, hookErr)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.Get().ErrorCommand, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	app := ui.NewApp(store, *workspace)
 
 	p := tea.NewProgram(app, tea.WithAltScreen())
 